@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+
+	"github.com/ahboujelben/go-crossword/cli/renderer"
+	"github.com/ahboujelben/go-crossword/modules/codeword"
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// codewordParseResult holds the parsed arguments of the codeword command
+type codewordParseResult struct {
+	Rows          int
+	Cols          int
+	CrosswordSeed int64
+	Threads       int
+	Shaper        crossword.Shaper
+	Reveals       int
+	Solution      bool
+	Renderer      renderer.Renderer
+}
+
+// parseCodewordArguments parses the arguments of the codeword command
+func parseCodewordArguments(args []string) (*codewordParseResult, error) {
+	flags := flag.NewFlagSet("codeword", flag.ExitOnError)
+	rows := flags.Int("rows", 13, "number of rows in the codeword ([3, 25])")
+	cols := flags.Int("cols", 13, "number of columns in the codeword ([3, 25])")
+	crosswordSeed := flags.Int64("seed", 0, "seed for the codeword generation ([0, 2^63-1], 0 for a random seed)")
+	threads := flags.Int("threads", runtime.NumCPU(), "number of goroutines to use (>= 1)")
+	shape := flags.String("shape", "classic", fmt.Sprintf("layout of the blank squares %v", crossword.ShaperNames()))
+	reveals := flags.Int("reveals", 2, "number of starter letters (>= 0), more are revealed if needed for a unique solution")
+	solution := flags.Bool("solution", false, "also render the solution")
+	compact := flags.Bool("compact", false, "compact rendering")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if !isSizeValid(*rows) || !isSizeValid(*cols) {
+		return nil, fmt.Errorf("invalid dimensions")
+	}
+
+	if !isSeedValid(*crosswordSeed) {
+		return nil, fmt.Errorf("invalid codeword seed")
+	}
+
+	if *threads < 1 {
+		return nil, fmt.Errorf("invalid number of goroutines")
+	}
+
+	if *reveals < 0 {
+		return nil, fmt.Errorf("invalid number of starter letters")
+	}
+
+	shaper, err := crossword.ShaperByName(*shape)
+	if err != nil {
+		return nil, err
+	}
+	if _, barred := shaper.(crossword.BarShaper); barred {
+		return nil, fmt.Errorf("a codeword can't be barred")
+	}
+
+	var render renderer.Renderer = renderer.NewStandardRenderer()
+	if *compact {
+		render = renderer.NewCompactRenderer()
+	}
+
+	return &codewordParseResult{
+		Rows:          *rows,
+		Cols:          *cols,
+		CrosswordSeed: *crosswordSeed,
+		Threads:       *threads,
+		Shaper:        shaper,
+		Reveals:       *reveals,
+		Solution:      *solution,
+		Renderer:      render,
+	}, nil
+}
+
+func generateCodeword(parseResult *codewordParseResult) error {
+	fmt.Println("Generating codeword...")
+	wordDict := dictionary.NewWordDictionary()
+	crosswordResult, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     parseResult.Rows,
+		Cols:     parseResult.Cols,
+		Seed:     parseResult.CrosswordSeed,
+		Threads:  parseResult.Threads,
+		WordDict: wordDict,
+		Shaper:   parseResult.Shaper,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the crossword seed also numbers the letters, so that it reproduces the
+	// whole codeword
+	cw, err := codeword.NewCodeword(crosswordResult.Crossword, codeword.CodewordConfig{
+		Reveals:  parseResult.Reveals,
+		WordDict: wordDict,
+		Seed:     crosswordResult.Seed,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n\n", parseResult.Renderer.RenderCodeword(cw, false))
+	if parseResult.Solution {
+		fmt.Printf("Solution:\n\n%s\n\n", parseResult.Renderer.RenderCodeword(cw, true))
+	}
+	fmt.Println("Codeword generated successfully!")
+	fmt.Printf("Starter letters: %d\n", cw.Reveals())
+	fmt.Printf("Seed: %d\n", crosswordResult.Seed)
+	return nil
+}
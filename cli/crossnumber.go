@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/cli/renderer"
+	"github.com/ahboujelben/go-crossword/modules/crossnumber"
+)
+
+// crossNumberParseResult holds the parsed arguments of the crossnumber command
+type crossNumberParseResult struct {
+	Rows            int
+	Cols            int
+	CrossNumberSeed int64
+	Threads         int
+	Properties      []crossnumber.Property
+	Solution        bool
+	Renderer        renderer.Renderer
+}
+
+// parseCrossNumberArguments parses the arguments of the crossnumber command
+func parseCrossNumberArguments(args []string) (*crossNumberParseResult, error) {
+	flags := flag.NewFlagSet("crossnumber", flag.ExitOnError)
+	rows := flags.Int("rows", 5, fmt.Sprintf("number of rows in the cross-number ([3, %d])", crossnumber.MaxDigits))
+	cols := flags.Int("cols", 5, fmt.Sprintf("number of columns in the cross-number ([3, %d])", crossnumber.MaxDigits))
+	crossNumberSeed := flags.Int64("seed", 0, "seed for the cross-number generation ([0, 2^63-1], 0 for a random seed)")
+	threads := flags.Int("threads", runtime.NumCPU(), "number of goroutines to use (>= 1)")
+	properties := flags.String("properties", "", "comma-separated properties the entries are clued by (default: all)")
+	solution := flags.Bool("solution", false, "also render the solution")
+	compact := flags.Bool("compact", false, "compact rendering")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *rows < 3 || *rows > crossnumber.MaxDigits || *cols < 3 || *cols > crossnumber.MaxDigits {
+		return nil, fmt.Errorf("invalid dimensions")
+	}
+
+	if !isSeedValid(*crossNumberSeed) {
+		return nil, fmt.Errorf("invalid cross-number seed")
+	}
+
+	if *threads < 1 {
+		return nil, fmt.Errorf("invalid number of goroutines")
+	}
+
+	var clueProperties []crossnumber.Property
+	if *properties != "" {
+		for _, name := range strings.Split(*properties, ",") {
+			property, err := crossnumber.PropertyByName(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			clueProperties = append(clueProperties, property)
+		}
+	}
+
+	var render renderer.Renderer = renderer.NewStandardRenderer()
+	if *compact {
+		render = renderer.NewCompactRenderer()
+	}
+
+	return &crossNumberParseResult{
+		Rows:            *rows,
+		Cols:            *cols,
+		CrossNumberSeed: *crossNumberSeed,
+		Threads:         *threads,
+		Properties:      clueProperties,
+		Solution:        *solution,
+		Renderer:        render,
+	}, nil
+}
+
+func generateCrossNumber(parseResult *crossNumberParseResult) error {
+	fmt.Println("Generating cross-number...")
+	result, err := crossnumber.NewCrossNumber(crossnumber.CrossNumberConfig{
+		Rows:       parseResult.Rows,
+		Cols:       parseResult.Cols,
+		Threads:    parseResult.Threads,
+		Properties: parseResult.Properties,
+		Seed:       parseResult.CrossNumberSeed,
+	})
+	if err != nil {
+		return err
+	}
+
+	cn := result.CrossNumber
+	fmt.Printf("\n%s\n", parseResult.Renderer.RenderCrossword(cn.Grid(), false))
+	for i, clue := range cn.Clues() {
+		if i == 0 || clue.Across != cn.Clues()[i-1].Across {
+			if clue.Across {
+				fmt.Println("\nAcross:")
+			} else {
+				fmt.Println("\nDown:")
+			}
+		}
+		fmt.Printf("%3d. (row %d, column %d) %s\n", clue.Number, clue.Row+1, clue.Column+1, clue.Text)
+	}
+	if parseResult.Solution {
+		fmt.Printf("\nSolution:\n\n%s\n", parseResult.Renderer.RenderCrossword(cn.Grid(), true))
+	}
+	fmt.Println("\nCross-number generated successfully!")
+	fmt.Printf("Seed: %d\n", result.Seed)
+	return nil
+}
@@ -2,22 +2,170 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/ahboujelben/go-crossword/cli/renderer"
 	"github.com/ahboujelben/go-crossword/modules/crossword"
 	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/fillin"
+	"github.com/ahboujelben/go-crossword/modules/history"
+	"github.com/ahboujelben/go-crossword/modules/lint"
 )
 
-func generateCrossword(parseResult *parseResult) {
+func generateCrossword(parseResult *parseResult) error {
 	fmt.Println("Generating crossword...")
-	crosswordResult := crossword.NewCrossword(crossword.CrosswordConfig{
-		Rows:     parseResult.Rows,
-		Cols:     parseResult.Cols,
-		Seed:     parseResult.CrosswordSeed,
-		Threads:  parseResult.Threads,
-		WordDict: dictionary.NewWordDictionary(),
-	})
+	wordDict, err := loadWordDictionary(parseResult.Frequencies)
+	if err != nil {
+		return err
+	}
+	config := crossword.CrosswordConfig{
+		Rows:          parseResult.Rows,
+		Cols:          parseResult.Cols,
+		Seed:          parseResult.CrosswordSeed,
+		Threads:       parseResult.Threads,
+		WordDict:      wordDict,
+		Shaper:        parseResult.Shaper,
+		Outline:       parseResult.Outline,
+		Mini:          parseResult.Mini,
+		MiniBlocks:    parseResult.MiniBlocks,
+		Words:         parseResult.Words,
+		Arrowword:     parseResult.Arrowword,
+		Exclude:       parseResult.Exclude,
+		Require:       parseResult.Require,
+		MinWordLength: parseResult.MinLength,
+		MaxWordLength: parseResult.MaxLength,
+		MinDensity:    parseResult.MinDensity,
+		MaxDensity:    parseResult.MaxDensity,
+		MinDifficulty: parseResult.MinDifficulty,
+		MaxDifficulty: parseResult.MaxDifficulty,
+		CommonWords:   parseResult.CommonWords,
+	}
+	if parseResult.MaxUnchecked >= 0 {
+		config.LimitUnchecked = true
+		config.MaxUncheckedRatio = float64(parseResult.MaxUnchecked) / 100
+	}
+	if parseResult.MaxLint >= 0 {
+		config.Accept = lint.Accept(wordDict, parseResult.MaxLint)
+	}
+	// crosswords whose fill-in puzzle may have several solutions are
+	// regenerated
+	if parseResult.FillIn {
+		accept := config.Accept
+		config.Accept = func(c *crossword.Crossword) bool {
+			return (accept == nil || accept(c)) && fillin.Accept(c)
+		}
+	}
+
+	// published crosswords are avoided for the configured number of days
+	var store *history.Store
+	now := time.Now()
+	if parseResult.History != nil {
+		store, err = history.Open(parseResult.History.File)
+		if err != nil {
+			return err
+		}
+		config = store.Apply(config, parseResult.History.since(now), parseResult.History.Policy)
+	}
+
+	crosswordResult, err := crossword.NewCrossword(config)
+	if err != nil {
+		return err
+	}
+
+	if parseResult.FillIn {
+		f, err := fillin.NewFillIn(crosswordResult.Crossword)
+		if err != nil {
+			return err
+		}
+		renderFillIn(parseResult.Renderer, f)
+	}
 
 	fmt.Printf("\n%s\n\n", parseResult.Renderer.RenderCrossword(crosswordResult.Crossword, true))
 	fmt.Println("Crossword generated successfully!")
 	fmt.Printf("Seed: %d\n", crosswordResult.Seed)
+	fmt.Printf("Difficulty: %s\n", crosswordResult.Difficulty)
+	if parseResult.CommonWords > 0 {
+		fmt.Println(commonWordsReport(parseResult.CommonWords, crosswordResult.CommonWords))
+	}
+	if parseResult.MinDensity > 0 || parseResult.MaxDensity > 0 {
+		fmt.Println(densityReport(parseResult.MinDensity, parseResult.MaxDensity, crosswordResult.MinDensity, crosswordResult.MaxDensity))
+	}
+	switch parseResult.Stats {
+	case "table":
+		fmt.Println()
+		printStats(crossword.Stats(crosswordResult.Crossword))
+	case "json":
+		if err := printStatsJSON(crossword.Stats(crosswordResult.Crossword)); err != nil {
+			return err
+		}
+	}
+
+	if parseResult.History != nil && parseResult.History.Record {
+		if err := store.Add(history.NewEntry(crosswordResult.Crossword, now)); err != nil {
+			return err
+		}
+		fmt.Printf("Recorded in %s\n", parseResult.History.File)
+	}
+	return nil
+}
+
+// frequenciesEnv is the environment variable naming the default frequency file
+const frequenciesEnv = "GO_CROSSWORD_FREQUENCIES"
+
+// loadWordDictionary returns the embedded dictionary, ranking its words by the
+// frequency file instead of the embedded frequencies when given
+func loadWordDictionary(frequencies string) (dictionary.WordDictionary, error) {
+	wordDict := dictionary.NewWordDictionary()
+	if frequencies == "" {
+		return wordDict, nil
+	}
+	file, err := os.Open(frequencies)
+	if err != nil {
+		return wordDict, fmt.Errorf("cannot read frequencies: %w", err)
+	}
+	defer file.Close()
+	return wordDict.WithFrequencies(file)
+}
+
+// commonWordsReport describes the words a crossword restricted to the
+// requested number of common words was filled with
+func commonWordsReport(requested, used int) string {
+	switch {
+	case used == requested:
+		return fmt.Sprintf("Words: the %d most common", used)
+	case used == 0:
+		return fmt.Sprintf("Words: all of them, the grid couldn't be filled with the %d most common", requested)
+	}
+	return fmt.Sprintf("Words: the %d most common, the grid couldn't be filled with the %d most common", used, requested)
+}
+
+// densityReport describes the proportion of blank squares of a crossword
+// generated within the requested density range, or within a higher one when
+// the grid couldn't be filled
+func densityReport(requestedMin, requestedMax, usedMin, usedMax float64) string {
+	used := densityPercents(usedMin, usedMax)
+	if usedMin == requestedMin && usedMax == requestedMax {
+		return fmt.Sprintf("Density: %s", used)
+	}
+	return fmt.Sprintf("Density: %s, the grid couldn't be filled at %s", used, densityPercents(requestedMin, requestedMax))
+}
+
+// densityPercents writes a density range in percents
+func densityPercents(minDensity, maxDensity float64) string {
+	if maxDensity == 0 {
+		return fmt.Sprintf("at least %.0f%%", 100*minDensity)
+	}
+	return fmt.Sprintf("%.0f-%.0f%%", 100*minDensity, 100*maxDensity)
+}
+
+// renderFillIn prints the grid of a fill-in puzzle, with its given letters,
+// followed by its answers grouped by length
+func renderFillIn(render renderer.Renderer, f *fillin.FillIn) {
+	fmt.Printf("\n%s\n\n", render.RenderCrossword(f.Puzzle(), true))
+	for _, group := range f.Words() {
+		fmt.Printf("%d letters: %s\n", group.Length, strings.ToUpper(strings.Join(group.Words, ", ")))
+	}
+	fmt.Printf("\nGiven letters: %d\n\nSolution:\n", f.Givens())
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/history"
+)
+
+// historyEnv is the environment variable naming the default history file
+const historyEnv = "GO_CROSSWORD_HISTORY"
+
+// dateLayout is the format of the dates read and printed by the history command
+const dateLayout = "2006-01-02"
+
+// historyOptions holds how a generated crossword uses the history of the
+// published crosswords
+type historyOptions struct {
+	File   string
+	Days   int
+	Policy history.Policy
+	Record bool
+}
+
+// since returns the start of the days avoided by the options
+func (o historyOptions) since(now time.Time) time.Time {
+	return now.AddDate(0, 0, -o.Days)
+}
+
+// runHistory runs the history command: add, list or prune
+func runHistory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing history command (add, list or prune)")
+	}
+
+	flags := flag.NewFlagSet("history "+args[0], flag.ExitOnError)
+	file := flags.String("file", os.Getenv(historyEnv), "history file, $"+historyEnv+" when not set")
+	var date *string
+	var days *int
+	switch args[0] {
+	case "add":
+		date = flags.String("date", time.Now().Format(dateLayout), "publication date of the crosswords (YYYY-MM-DD)")
+	case "list":
+		days = flags.Int("days", 0, "only list the crosswords published in the last days (0 for all)")
+	case "prune":
+		days = flags.Int("days", 90, "number of days the published crosswords are kept for (>= 0)")
+	default:
+		return fmt.Errorf("unknown history command %q", args[0])
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("no history file, set -file or $%s", historyEnv)
+	}
+	if days != nil && *days < 0 {
+		return fmt.Errorf("invalid number of days")
+	}
+	store, err := history.Open(*file)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		return addHistory(store, *date, flags.Args())
+	case "list":
+		listHistory(store, *days)
+	case "prune":
+		removed, err := store.Prune(time.Now().AddDate(0, 0, -*days))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pruned %d crosswords\n", removed)
+	}
+	return nil
+}
+
+// addHistory records the crosswords of the grid files, written in the format
+// read by crossword.ParseCrossword, as published on the date
+func addHistory(store *history.Store, date string, files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no grid files to add")
+	}
+	day, err := time.ParseInLocation(dateLayout, date, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", date)
+	}
+
+	entries := []history.Entry{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("cannot read grid: %w", err)
+		}
+		grid, err := crossword.ParseCrossword(string(content))
+		if err != nil {
+			return fmt.Errorf("invalid grid %s: %w", file, err)
+		}
+		entries = append(entries, history.NewEntry(grid, day))
+	}
+	if err := store.Add(entries...); err != nil {
+		return err
+	}
+	fmt.Printf("Added %d crosswords\n", len(entries))
+	return nil
+}
+
+// listHistory prints the crosswords published in the last days, all of them
+// if days is 0
+func listHistory(store *history.Store, days int) {
+	since := time.Time{}
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	for _, entry := range store.Entries() {
+		if entry.Date.Before(since) {
+			continue
+		}
+		rows := strings.Count(entry.Layout, "\n")
+		columns := strings.Index(entry.Layout, "\n")
+		fmt.Printf("%s  %dx%d  %s\n", entry.Date.Format(dateLayout), rows, columns, strings.ToUpper(strings.Join(entry.Answers, ", ")))
+	}
+}
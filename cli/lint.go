@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/lint"
+)
+
+// runLint runs the lint command, reviewing the fill of grid files written in
+// the format read by crossword.ParseCrossword
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	frequencies := flags.String("frequencies", os.Getenv(frequenciesEnv), "file of the dictionary words from the most common to the rarest, replacing the embedded ranking, $"+frequenciesEnv+" when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no grid files to lint")
+	}
+
+	wordDict, err := loadWordDictionary(*frequencies)
+	if err != nil {
+		return err
+	}
+	for _, file := range flags.Args() {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("cannot read grid: %w", err)
+		}
+		grid, err := crossword.ParseCrossword(string(content))
+		if err != nil {
+			return fmt.Errorf("invalid grid %s: %w", file, err)
+		}
+		findings := lint.Lint(grid, wordDict)
+		fmt.Printf("%s: %d findings, penalty %d\n", file, len(findings), lint.Penalty(findings))
+		for _, finding := range findings {
+			fmt.Printf("  %s\n", finding)
+		}
+	}
+	return nil
+}
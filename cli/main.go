@@ -2,14 +2,65 @@ package main
 
 import (
 	"fmt"
+	"os"
 )
 
 func main() {
-	parseResult, err := parseArguments()
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	var err error
+	switch command {
+	case "wordsearch":
+		err = runWordSearch(os.Args[2:])
+	case "codeword":
+		err = runCodeword(os.Args[2:])
+	case "crossnumber":
+		err = runCrossNumber(os.Args[2:])
+	case "history":
+		err = runHistory(os.Args[2:])
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "stats":
+		err = runStats(os.Args[2:])
+	default:
+		err = runCrossword()
+	}
 	if err != nil {
 		fmt.Println(fmt.Errorf("Something is not right: %w", err))
-		return
 	}
+}
+
+func runCrossword() error {
+	parseResult, err := parseArguments()
+	if err != nil {
+		return err
+	}
+	return generateCrossword(parseResult)
+}
+
+func runWordSearch(args []string) error {
+	parseResult, err := parseWordSearchArguments(args)
+	if err != nil {
+		return err
+	}
+	return generateWordSearch(parseResult)
+}
+
+func runCodeword(args []string) error {
+	parseResult, err := parseCodewordArguments(args)
+	if err != nil {
+		return err
+	}
+	return generateCodeword(parseResult)
+}
 
-	generateCrossword(parseResult)
+func runCrossNumber(args []string) error {
+	parseResult, err := parseCrossNumberArguments(args)
+	if err != nil {
+		return err
+	}
+	return generateCrossNumber(parseResult)
 }
@@ -3,8 +3,14 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
 
 	"github.com/ahboujelben/go-crossword/cli/renderer"
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/history"
 )
 
 // parseResult holds the parsed command-line arguments
@@ -13,16 +19,60 @@ type parseResult struct {
 	Cols          int
 	CrosswordSeed int64
 	Threads       int
+	Shaper        crossword.Shaper
+	Outline       crossword.Outline
+	Mini          bool
+	MiniBlocks    int
+	Words         []string
+	FillIn        bool
+	Arrowword     bool
+	Exclude       []string
+	Require       []string
+	MinLength     int
+	MaxLength     int
+	MinDensity    float64
+	MaxDensity    float64
+	MaxUnchecked  int
+	History       *historyOptions
+	MinDifficulty float64
+	MaxDifficulty float64
+	Frequencies   string
+	CommonWords   int
+	MaxLint       int
+	Stats         string
 	Renderer      renderer.Renderer
 }
 
 // parseArguments parses command-line arguments and returns a ParseResult
 func parseArguments() (*parseResult, error) {
-	rows := flag.Int("rows", 13, "number of rows in the crossword ([3, 15])")
-	cols := flag.Int("cols", 13, "number of columns in the crossword ([3, 15])")
+	rows := flag.Int("rows", 13, "number of rows in the crossword ([3, 25])")
+	cols := flag.Int("cols", 13, "number of columns in the crossword ([3, 25])")
 	crosswordSeed := flag.Int64("seed", 0, "seed for the crossword generation ([0, 2^63-1], 0 for a random seed)")
-	threads := flag.Int("threads", 100, "number of goroutines to use (>= 1)")
+	threads := flag.Int("threads", runtime.NumCPU(), "number of goroutines to use (>= 1)")
 	compact := flag.Bool("compact", false, "compact rendering")
+	arrowword := flag.Bool("arrowword", false, "generate an arrowword, whose clue squares point to their answers")
+	fillIn := flag.Bool("fillin", false, "render a fill-in puzzle: the empty grid and its answers grouped by length")
+	shape := flag.String("shape", "classic", fmt.Sprintf("layout of the blank squares %v", crossword.ShaperNames()))
+	outline := flag.String("outline", "", fmt.Sprintf("outline of a non-rectangular grid %v, or a template file marking the squares outside it with %q", crossword.OutlineNames(), crossword.Masked))
+	mini := flag.Bool("mini", false, "generate a dense mini puzzle ([3, 6] rows and columns)")
+	blocks := flag.Int("blocks", 0, "number of blank corner squares in a mini puzzle ([0, 4])")
+	wordsFile := flag.String("words", "", "file of words to build a criss-cross from, rows and cols bounding its size when set")
+	exclude := flag.String("exclude", "", "comma-separated words the crossword must not contain")
+	require := flag.String("require", "", "comma-separated words the crossword must contain")
+	minLength := flag.Int("min-length", 0, "shortest word of the crossword (0 for no bound)")
+	maxLength := flag.Int("max-length", 0, "longest word of the crossword (0 for no bound)")
+	density := flag.String("density", "", "range of the proportion of blank squares in percents, such as 15-20")
+	maxUnchecked := flag.Int("max-unchecked", -1, "highest percentage of letters belonging to a single word ([0, 100], -1 for no limit)")
+	historyFile := flag.String("history", os.Getenv(historyEnv), "history file of the published crosswords to avoid, $"+historyEnv+" when not set")
+	historyDays := flag.Int("history-days", 30, "number of days a published crossword is avoided for (>= 0)")
+	historyPolicy := flag.String("history-policy", "forbid", "how recent answers and layouts are avoided (forbid or penalize)")
+	minDifficulty := flag.Float64("min-difficulty", 0, "lowest difficulty score of the crossword ([0, 1])")
+	maxDifficulty := flag.Float64("max-difficulty", 0, "highest difficulty score of the crossword ([0, 1], 0 for no bound)")
+	frequencies := flag.String("frequencies", os.Getenv(frequenciesEnv), "file of the dictionary words from the most common to the rarest, replacing the embedded ranking, $"+frequenciesEnv+" when not set")
+	level := flag.String("level", "", "restrict the words to the most common ones of a level (easy, medium or hard)")
+	maxLint := flag.Int("max-lint", -1, "reject crosswords whose lint penalty exceeds it (-1 for no limit)")
+	stats := flag.String("stats", "", "also print the statistics of the crossword (table or json)")
+	record := flag.Bool("record", false, "add the generated crossword to the history as published today")
 
 	flag.Parse()
 
@@ -38,6 +88,120 @@ func parseArguments() (*parseResult, error) {
 		return nil, fmt.Errorf("invalid number of goroutines")
 	}
 
+	if *mini && (!isMiniSizeValid(*rows) || !isMiniSizeValid(*cols)) {
+		return nil, fmt.Errorf("invalid dimensions for a mini puzzle")
+	}
+
+	if *blocks < 0 || *blocks > 4 {
+		return nil, fmt.Errorf("invalid number of blocks")
+	}
+
+	var shaper crossword.Shaper
+	if !*mini {
+		var err error
+		shaper, err = crossword.ShaperByName(*shape)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, barred := shaper.(crossword.BarShaper); barred && *compact {
+		return nil, fmt.Errorf("compact rendering can't draw the bars of a barred crossword")
+	}
+
+	// flags set on the command line, whose defaults may be overridden
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var gridOutline crossword.Outline
+	if *outline != "" {
+		var err error
+		if gridOutline, err = parseOutline(*outline); err != nil {
+			return nil, err
+		}
+		// the grid of a template has its size unless a size is given
+		if template, ok := gridOutline.(crossword.TemplateOutline); ok {
+			templateRows, templateColumns := template.Size()
+			if !set["rows"] {
+				*rows = templateRows
+			}
+			if !set["cols"] {
+				*cols = templateColumns
+			}
+			if !isSizeValid(*rows) || !isSizeValid(*cols) {
+				return nil, fmt.Errorf("invalid dimensions")
+			}
+		}
+		if *arrowword {
+			return nil, fmt.Errorf("an arrowword can't have an outline")
+		}
+	}
+
+	var words []string
+	if *wordsFile != "" {
+		content, err := os.ReadFile(*wordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read words: %w", err)
+		}
+		words = strings.Fields(string(content))
+
+		// the grid of a criss-cross fits its words unless a size is given
+		if !set["rows"] {
+			*rows = 0
+		}
+		if !set["cols"] {
+			*cols = 0
+		}
+	}
+
+	if *minLength < 0 || *maxLength < 0 || (*maxLength > 0 && *maxLength < max(*minLength, 2)) {
+		return nil, fmt.Errorf("invalid word length range")
+	}
+
+	var minDensity, maxDensity float64
+	if *density != "" {
+		var err error
+		if minDensity, maxDensity, err = crossword.ParseDensity(*density); err != nil {
+			return nil, err
+		}
+	}
+
+	if *maxUnchecked < -1 || *maxUnchecked > 100 {
+		return nil, fmt.Errorf("invalid percentage of unchecked letters")
+	}
+
+	if *minDifficulty < 0 || *minDifficulty > 1 || *maxDifficulty < 0 || *maxDifficulty > 1 ||
+		(*maxDifficulty > 0 && *minDifficulty > *maxDifficulty) {
+		return nil, fmt.Errorf("invalid difficulty range")
+	}
+
+	commonWords := 0
+	if *level != "" {
+		var err error
+		if commonWords, err = dictionary.LevelWords(*level); err != nil {
+			return nil, err
+		}
+	}
+
+	if *stats != "" && *stats != "table" && *stats != "json" {
+		return nil, fmt.Errorf("invalid statistics format %q", *stats)
+	}
+
+	var historyOpts *historyOptions
+	if *historyFile != "" {
+		policy, err := history.ParsePolicy(*historyPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if *historyDays < 0 {
+			return nil, fmt.Errorf("invalid number of history days")
+		}
+		historyOpts = &historyOptions{File: *historyFile, Days: *historyDays, Policy: policy, Record: *record}
+	} else if *record {
+		return nil, fmt.Errorf("recording a crossword needs a history file")
+	}
+
 	var render renderer.Renderer = renderer.NewStandardRenderer()
 	if *compact {
 		render = renderer.NewCompactRenderer()
@@ -48,10 +212,56 @@ func parseArguments() (*parseResult, error) {
 		Cols:          *cols,
 		CrosswordSeed: *crosswordSeed,
 		Threads:       *threads,
+		Shaper:        shaper,
+		Outline:       gridOutline,
+		Mini:          *mini,
+		MiniBlocks:    *blocks,
+		Words:         words,
+		FillIn:        *fillIn,
+		Arrowword:     *arrowword,
+		Exclude:       splitWords(*exclude),
+		Require:       splitWords(*require),
+		MinLength:     *minLength,
+		MaxLength:     *maxLength,
+		MinDensity:    minDensity,
+		MaxDensity:    maxDensity,
+		MaxUnchecked:  *maxUnchecked,
+		History:       historyOpts,
+		MinDifficulty: *minDifficulty,
+		MaxDifficulty: *maxDifficulty,
+		Frequencies:   *frequencies,
+		CommonWords:   commonWords,
+		MaxLint:       *maxLint,
+		Stats:         *stats,
 		Renderer:      render,
 	}, nil
 }
 
+// parseOutline returns the built-in outline of the given name, or else the
+// outline of the template file of the given path
+func parseOutline(value string) (crossword.Outline, error) {
+	if outline, err := crossword.OutlineByName(value); err == nil {
+		return outline, nil
+	}
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("unknown outline %q, neither one of %v nor a template file", value, crossword.OutlineNames())
+	}
+	return crossword.ParseOutline(string(content))
+}
+
+// splitWords splits a comma-separated list of words, returning nil for an
+// empty list
+func splitWords(list string) []string {
+	var words []string
+	for _, word := range strings.Split(list, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
 // isSeedValid checks if a seed value is valid
 func isSeedValid(seed int64) bool {
 	return seed >= 0
@@ -59,5 +269,10 @@ func isSeedValid(seed int64) bool {
 
 // isSizeValid checks if a crossword size is valid
 func isSizeValid(size int) bool {
-	return size >= 3 && size <= 15
+	return size >= 3 && size <= 25
+}
+
+// isMiniSizeValid checks if a mini puzzle size is valid
+func isMiniSizeValid(size int) bool {
+	return size >= 3 && size <= 6
 }
@@ -1,9 +1,20 @@
 package renderer
 
 import (
+	"fmt"
+
+	"github.com/ahboujelben/go-crossword/modules/codeword"
 	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/wordsearch"
 )
 
+// compactArrows draws the arrows of clue squares on two characters.
+var compactArrows = map[crossword.Arrow]string{
+	crossword.ArrowRight:                       "→ ",
+	crossword.ArrowDown:                        "↓ ",
+	crossword.ArrowRight | crossword.ArrowDown: "→↓",
+}
+
 type CompactRenderer struct {
 }
 
@@ -20,17 +31,71 @@ func (f CompactRenderer) RenderCrossword(c *crossword.Crossword, solved bool) st
 	return result
 }
 
+func (f CompactRenderer) RenderWordSearch(ws *wordsearch.WordSearch, answerKey bool) string {
+	var result string
+	grid := ws.Grid()
+	for letter := crossword.CrosswordLetter(grid); letter != nil; letter = letter.Next() {
+		if answerKey && !ws.IsAnswer(letter.Row(), letter.Column()) {
+			result += ". "
+		} else {
+			result += string(letter.GetValue()+'A'-'a') + " "
+		}
+		if letter.Column() == grid.Columns()-1 && letter.Row() != grid.Rows()-1 {
+			result += "\n"
+		}
+	}
+
+	return result
+}
+
+func (f CompactRenderer) RenderCodeword(cw *codeword.Codeword, solved bool) string {
+	var result string
+	grid := cw.Grid()
+	for letter := crossword.CrosswordLetter(grid); letter != nil; letter = letter.Next() {
+		number := cw.NumberAt(letter.Row(), letter.Column())
+		switch {
+		case number == 0:
+			result += "██ "
+		case solved || cw.IsRevealed(number):
+			result += fmt.Sprintf(" %c ", letter.GetValue()+'A'-'a')
+		default:
+			result += fmt.Sprintf("%2d ", number)
+		}
+		if letter.Column() == grid.Columns()-1 {
+			result += "\n"
+		}
+	}
+
+	result += "\n"
+	for number := 1; number <= cw.Size(); number++ {
+		letter := byte('?')
+		if solved || cw.IsRevealed(number) {
+			letter = cw.Letter(number) + 'A' - 'a'
+		}
+		result += fmt.Sprintf("%2d=%c ", number, letter)
+		if number%codewordKeyColumns == 0 && number != cw.Size() {
+			result += "\n"
+		}
+	}
+
+	return result
+}
+
 func getFormattedLetters(c *crossword.Crossword, solved bool) chan string {
 	ch := make(chan string)
 	go func() {
 		for letter := crossword.CrosswordLetter(c); letter != nil; letter = letter.Next() {
 			switch {
+			case letter.IsMasked():
+				ch <- "  "
+			case letter.IsClue():
+				ch <- compactArrows[letter.Arrow()]
 			case letter.IsBlank():
 				ch <- "█ "
 			case letter.IsEmpty() || !solved:
 				ch <- ". "
 			default:
-				ch <- string(letter.GetValue()+'A'-'a') + " "
+				ch <- string(upper(letter.GetValue())) + " "
 			}
 			if letter.Column() == c.Columns()-1 && letter.Row() != c.Rows()-1 {
 				ch <- "\n"
@@ -1,9 +1,20 @@
 package renderer
 
 import (
+	"github.com/ahboujelben/go-crossword/modules/codeword"
 	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/wordsearch"
 )
 
+// codewordKeyColumns is the number of letters per row of a codeword key.
+const codewordKeyColumns = 13
+
 type Renderer interface {
 	RenderCrossword(c *crossword.Crossword, solved bool) string
+	// RenderWordSearch renders the letters of a word search. The answer key
+	// only shows the letters of the hidden words.
+	RenderWordSearch(ws *wordsearch.WordSearch, answerKey bool) string
+	// RenderCodeword renders the numbered grid of a codeword followed by its
+	// key, showing only the revealed letters unless solved.
+	RenderCodeword(cw *codeword.Codeword, solved bool) string
 }
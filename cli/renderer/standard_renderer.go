@@ -2,8 +2,11 @@ package renderer
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/ahboujelben/go-crossword/modules/codeword"
 	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/wordsearch"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
@@ -31,6 +34,10 @@ func NewStandardRenderer() StandardRenderer {
 }
 
 func (f StandardRenderer) RenderCrossword(c *crossword.Crossword, solved bool) string {
+	if c.IsBarred() || c.HasMask() {
+		return renderGrid(newCrosswordCharmWrapper(c, solved))
+	}
+
 	crosswordGrid := getBorderTable().
 		BorderRow(true).
 		StyleFunc(func(row, col int) lipgloss.Style {
@@ -45,6 +52,49 @@ func (f StandardRenderer) RenderCrossword(c *crossword.Crossword, solved bool) s
 	return crosswordGrid.Render()
 }
 
+func (f StandardRenderer) RenderWordSearch(ws *wordsearch.WordSearch, answerKey bool) string {
+	wordSearchGrid := getBorderTable().
+		BorderRow(true).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := lipgloss.NewStyle()
+			if row == 0 || col == 0 {
+				s = s.Foreground(lipgloss.Color("#00ff00"))
+			} else if answerKey && ws.IsAnswer(row-1, col-1) {
+				s = s.Bold(true)
+			}
+			return s
+		}).
+		Data(newWordSearchCharmWrapper(ws, answerKey))
+
+	return wordSearchGrid.Render()
+}
+
+func (f StandardRenderer) RenderCodeword(cw *codeword.Codeword, solved bool) string {
+	codewordGrid := getBorderTable().
+		BorderRow(true).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := lipgloss.NewStyle()
+			if row == 0 || col == 0 {
+				s = s.Foreground(lipgloss.Color("#00ff00"))
+			}
+			return s
+		}).
+		Data(newCodewordCharmWrapper(cw, solved))
+
+	keyTable := getBorderTable().
+		BorderRow(true).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := lipgloss.NewStyle()
+			if row%2 == 0 {
+				s = s.Foreground(lipgloss.Color("#00ff00"))
+			}
+			return s
+		}).
+		Data(newCodewordKeyCharmWrapper(cw, solved))
+
+	return codewordGrid.Render() + "\n" + keyTable.Render()
+}
+
 type crosswordCharmWrapper struct {
 	*crossword.Crossword
 	solved bool
@@ -70,18 +120,294 @@ func (w *crosswordCharmWrapper) At(row, column int) string {
 		return "   "
 	}
 	if row == 0 {
-		return fmt.Sprintf(" %-2d", column)
+		return formatHeader(column)
 	}
 	if column == 0 {
-		return fmt.Sprintf(" %-2d", row)
+		return formatHeader(row)
 	}
 	letter := crossword.CrosswordLetterAt(w.Crossword, row-1, column-1)
 	switch {
+	case letter.IsMasked():
+		// the squares outside the outline are shaded, so that the ones
+		// boxed in by its borders don't look like empty squares
+		return "░░░"
+	case letter.IsClue():
+		return formatArrow(letter.Arrow())
 	case letter.IsBlank():
 		return "▐█▌"
 	case letter.IsEmpty() || !w.solved:
 		return "   "
 	default:
-		return fmt.Sprintf(" %c ", letter.GetValue()+'A'-'a')
+		return fmt.Sprintf(" %c ", upper(letter.GetValue()))
+	}
+}
+
+type wordSearchCharmWrapper struct {
+	*wordsearch.WordSearch
+	answerKey bool
+}
+
+func newWordSearchCharmWrapper(ws *wordsearch.WordSearch, answerKey bool) *wordSearchCharmWrapper {
+	return &wordSearchCharmWrapper{
+		WordSearch: ws,
+		answerKey:  answerKey,
+	}
+}
+
+func (w *wordSearchCharmWrapper) Columns() int {
+	return w.Grid().Columns() + 1
+}
+
+func (w *wordSearchCharmWrapper) Rows() int {
+	return w.Grid().Rows() + 1
+}
+
+func (w *wordSearchCharmWrapper) At(row, column int) string {
+	if row == 0 && column == 0 {
+		return "   "
+	}
+	if row == 0 {
+		return formatHeader(column)
+	}
+	if column == 0 {
+		return formatHeader(row)
+	}
+	if w.answerKey && !w.IsAnswer(row-1, column-1) {
+		return "   "
+	}
+	letter := crossword.CrosswordLetterAt(w.Grid(), row-1, column-1)
+	return fmt.Sprintf(" %c ", letter.GetValue()+'A'-'a')
+}
+
+type codewordCharmWrapper struct {
+	*codeword.Codeword
+	solved bool
+}
+
+func newCodewordCharmWrapper(cw *codeword.Codeword, solved bool) *codewordCharmWrapper {
+	return &codewordCharmWrapper{
+		Codeword: cw,
+		solved:   solved,
+	}
+}
+
+func (w *codewordCharmWrapper) Columns() int {
+	return w.Grid().Columns() + 1
+}
+
+func (w *codewordCharmWrapper) Rows() int {
+	return w.Grid().Rows() + 1
+}
+
+func (w *codewordCharmWrapper) At(row, column int) string {
+	if row == 0 && column == 0 {
+		return "   "
+	}
+	if row == 0 {
+		return formatHeader(column)
+	}
+	if column == 0 {
+		return formatHeader(row)
+	}
+	number := w.NumberAt(row-1, column-1)
+	switch {
+	case number == 0:
+		return "▐█▌"
+	case w.solved || w.IsRevealed(number):
+		return fmt.Sprintf(" %c ", w.Letter(number)+'A'-'a')
+	default:
+		return formatHeader(number)
+	}
+}
+
+// codewordKeyCharmWrapper lays out the key of a codeword as rows of numbers,
+// each followed by a row of the letters they stand for.
+type codewordKeyCharmWrapper struct {
+	*codeword.Codeword
+	solved bool
+}
+
+func newCodewordKeyCharmWrapper(cw *codeword.Codeword, solved bool) *codewordKeyCharmWrapper {
+	return &codewordKeyCharmWrapper{
+		Codeword: cw,
+		solved:   solved,
+	}
+}
+
+func (w *codewordKeyCharmWrapper) Columns() int {
+	return min(w.Size(), codewordKeyColumns)
+}
+
+func (w *codewordKeyCharmWrapper) Rows() int {
+	return 2 * ((w.Size() + codewordKeyColumns - 1) / codewordKeyColumns)
+}
+
+func (w *codewordKeyCharmWrapper) At(row, column int) string {
+	number := row/2*codewordKeyColumns + column + 1
+	switch {
+	case number > w.Size():
+		return "   "
+	case row%2 == 0:
+		return formatHeader(number)
+	case w.solved || w.IsRevealed(number):
+		return fmt.Sprintf(" %c ", w.Letter(number)+'A'-'a')
+	default:
+		return "   "
+	}
+}
+
+// barredJunctions are the box drawing characters joining four borders, indexed
+// by which of the up, down, left and right borders are heavy (bits 3 to 0).
+var barredJunctions = []rune("┼┾┽┿╁╆╅╈╀╄╃╇╂╊╉╋")
+
+// gridJunctions are the box drawing characters joining light borders, indexed
+// by which of the up, down, left and right borders are drawn (bits 3 to 0).
+var gridJunctions = []rune(" ╶╴─╷╭╮┬╵╰╯┴│├┤┼")
+
+// barredEdgeJunctions are the box drawing characters joining three borders
+// whose middle one is heavy, indexed by the drawn and the heavy borders as in
+// gridJunctions.
+var barredEdgeJunctions = map[[2]int]rune{
+	{7, 4}:  '┰',
+	{11, 8}: '┸',
+	{13, 1}: '┝',
+	{14, 2}: '┥',
+}
+
+// renderGrid draws the grid of a barred or non-rectangular crossword like the
+// border table of the other crosswords, which can neither draw bars as heavy
+// borders nor leave out the borders of the squares outside the outline.
+func renderGrid(w *crosswordCharmWrapper) string {
+	borderColor := blackColor
+	if lipgloss.HasDarkBackground() {
+		borderColor = whiteColor
+	}
+	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00"))
+
+	rows, columns := w.Rows(), w.Columns()
+	// present tells whether a cell of the table is drawn, the first row and
+	// column being headers and the masked squares being left out
+	present := func(row, column int) bool {
+		if row < 0 || column < 0 || row >= rows || column >= columns {
+			return false
+		}
+		return row == 0 || column == 0 ||
+			!crossword.CrosswordLetterAt(w.Crossword, row-1, column-1).IsMasked()
+	}
+	// rightBar and bottomBar tell whether the border on the right of or below
+	// a cell of the table is a bar
+	rightBar := func(row, column int) bool {
+		return row > 0 && column > 0 && column < columns-1 && row < rows &&
+			crossword.CrosswordLetterAt(w.Crossword, row-1, column-1).Bars()&crossword.BarRight != 0
+	}
+	bottomBar := func(row, column int) bool {
+		return row > 0 && column > 0 && row < rows-1 && column < columns &&
+			crossword.CrosswordLetterAt(w.Crossword, row-1, column-1).Bars()&crossword.BarBottom != 0
+	}
+	// the borders on the left of and above a cell of the table, 0 when there
+	// is none, 1 when light and 2 when heavy
+	leftBorder := func(row, column int) int {
+		switch {
+		case !present(row, column) && !present(row, column-1):
+			return 0
+		case rightBar(row, column-1):
+			return 2
+		}
+		return 1
+	}
+	topBorder := func(row, column int) int {
+		switch {
+		case !present(row, column) && !present(row-1, column):
+			return 0
+		case bottomBar(row-1, column):
+			return 2
+		}
+		return 1
+	}
+	// junction joins the borders meeting at the top left corner of a cell
+	junction := func(row, column int) rune {
+		borders := []int{leftBorder(row-1, column), leftBorder(row, column), topBorder(row, column-1), topBorder(row, column)}
+		drawn, heavy := 0, 0
+		for _, border := range borders {
+			drawn, heavy = drawn<<1, heavy<<1
+			if border > 0 {
+				drawn |= 1
+			}
+			if border == 2 {
+				heavy |= 1
+			}
+		}
+		if drawn == 15 {
+			return barredJunctions[heavy]
+		}
+		if junction, ok := barredEdgeJunctions[[2]int{drawn, heavy}]; ok {
+			return junction
+		}
+		return gridJunctions[drawn]
+	}
+
+	var b strings.Builder
+	border := func(line string) {
+		b.WriteString(borderStyle.Render(line))
+	}
+	horizontalBorders := func(row int) {
+		line := ""
+		for column := range columns + 1 {
+			line += string(junction(row, column))
+			if column == columns {
+				break
+			}
+			line += []string{"   ", "───", "━━━"}[topBorder(row, column)]
+		}
+		border(line)
+		b.WriteString("\n")
+	}
+
+	for row := range rows {
+		horizontalBorders(row)
+		for column := range columns + 1 {
+			border([]string{" ", "│", "┃"}[leftBorder(row, column)])
+			if column == columns {
+				break
+			}
+			cell := w.At(row, column)
+			if row == 0 || column == 0 {
+				cell = headerStyle.Render(cell)
+			}
+			b.WriteString(cell)
+		}
+		b.WriteString("\n")
+	}
+	horizontalBorders(rows)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatArrow draws the arrows of a clue square pointing to the words on its
+// right and below.
+func formatArrow(arrow crossword.Arrow) string {
+	switch arrow {
+	case crossword.ArrowRight:
+		return " → "
+	case crossword.ArrowDown:
+		return " ↓ "
+	default:
+		return "→ ↓"
+	}
+}
+
+// upper returns the letter of a square in upper case, the digits of
+// cross-numbers being left as they are.
+func upper(value byte) byte {
+	if value >= 'a' && value <= 'z' {
+		return value + 'A' - 'a'
 	}
+	return value
+}
+
+// formatHeader right-aligns a row or column number on the middle of its cell,
+// so that units line up with the letters below and two-digit numbers don't
+// overflow.
+func formatHeader(number int) string {
+	return fmt.Sprintf("%2d ", number)
 }
@@ -0,0 +1,49 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+)
+
+func TestRenderMaskedSquares(t *testing.T) {
+	testCases := []struct {
+		name  string
+		grid  string
+		lines map[int]string
+	}{
+		{
+			name: "masked squares next to the headers",
+			grid: "#ab\ncde\n#fg",
+			lines: map[int]string{
+				3: "│ 1 │░░░│   │   │",
+				5: "│ 2 │   │   │   │",
+				7: "│ 3 │░░░│   │   │",
+			},
+		},
+		{
+			name: "masked square inside the outline",
+			grid: "abc\nd#e\nfgh",
+			lines: map[int]string{
+				5: "│ 2 │   │░░░│   │",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := crossword.ParseCrossword(tc.grid)
+			if err != nil {
+				t.Fatalf("ParseCrossword() returned an unexpected error: %v", err)
+			}
+
+			lines := strings.Split(NewStandardRenderer().RenderCrossword(c, false), "\n")
+			for i, expected := range tc.lines {
+				if lines[i] != expected {
+					t.Errorf("Expected line %d to be %q, but got %q", i, expected, lines[i])
+				}
+			}
+		})
+	}
+}
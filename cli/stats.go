@@ -0,0 +1,86 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+)
+
+// runStats runs the stats command, printing the statistics of grid files
+// written in the format read by crossword.ParseCrossword
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the statistics as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no grid files")
+	}
+
+	for i, file := range flags.Args() {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("cannot read grid: %w", err)
+		}
+		grid, err := crossword.ParseCrossword(string(content))
+		if err != nil {
+			return fmt.Errorf("invalid grid %s: %w", file, err)
+		}
+		if *asJSON {
+			if err := printStatsJSON(crossword.Stats(grid)); err != nil {
+				return err
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", file)
+		printStats(crossword.Stats(grid))
+	}
+	return nil
+}
+
+// printStats prints the statistics of a crossword as a table
+func printStats(s crossword.Statistics) {
+	lengths := []string{}
+	for _, length := range slices.Sorted(maps.Keys(s.Lengths)) {
+		lengths = append(lengths, fmt.Sprintf("%d: %d", length, s.Lengths[length]))
+	}
+	letters := slices.SortedFunc(maps.Keys(s.Letters), func(a, b string) int {
+		return cmp.Or(s.Letters[b]-s.Letters[a], strings.Compare(a, b))
+	})
+	for i, letter := range letters {
+		letters[i] = fmt.Sprintf("%s: %d", strings.ToUpper(letter), s.Letters[letter])
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Size\t%dx%d\n", s.Rows, s.Columns)
+	fmt.Fprintf(w, "Words\t%d (%d across, %d down)\n", s.Words, s.Across, s.Down)
+	fmt.Fprintf(w, "Word lengths\t%s\n", strings.Join(lengths, ", "))
+	fmt.Fprintf(w, "Average length\t%.2f\n", s.AverageLength)
+	fmt.Fprintf(w, "Longest\t%s\n", strings.ToUpper(strings.Join(s.Longest, ", ")))
+	fmt.Fprintf(w, "Blanks\t%d (%.1f%%)\n", s.Blanks, s.BlankRatio*100)
+	if s.Masked > 0 {
+		fmt.Fprintf(w, "Masked\t%d\n", s.Masked)
+	}
+	fmt.Fprintf(w, "Checked letters\t%d\n", s.Checked)
+	fmt.Fprintf(w, "Unchecked letters\t%d\n", s.Unchecked)
+	fmt.Fprintf(w, "Letters\t%s\n", strings.Join(letters, ", "))
+	fmt.Fprintf(w, "Scrabble score\t%d\n", s.ScrabbleScore)
+	w.Flush()
+}
+
+// printStatsJSON prints the statistics of a crossword as a line of JSON
+func printStatsJSON(s crossword.Statistics) error {
+	return json.NewEncoder(os.Stdout).Encode(s)
+}
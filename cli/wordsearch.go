@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/cli/renderer"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/wordsearch"
+)
+
+// wordSearchParseResult holds the parsed arguments of the wordsearch command
+type wordSearchParseResult struct {
+	Config    wordsearch.WordSearchConfig
+	AnswerKey bool
+	Renderer  renderer.Renderer
+}
+
+// parseWordSearchArguments parses the arguments of the wordsearch command
+func parseWordSearchArguments(args []string) (*wordSearchParseResult, error) {
+	flags := flag.NewFlagSet("wordsearch", flag.ExitOnError)
+	rows := flags.Int("rows", 12, "number of rows in the word search ([3, 25])")
+	cols := flags.Int("cols", 12, "number of columns in the word search ([3, 25])")
+	seed := flags.Int64("seed", 0, "seed for the word search generation ([0, 2^63-1], 0 for a random seed)")
+	wordsFile := flags.String("words", "", "file of words to hide, sampled from the dictionary when not set")
+	count := flags.Int("count", 12, "number of words sampled from the dictionary (>= 1)")
+	minLength := flags.Int("min", 4, "minimum length of the words sampled from the dictionary")
+	maxLength := flags.Int("max", 0, "maximum length of the words sampled from the dictionary (0 for the grid size)")
+	directions := flags.String("directions", "all", "comma-separated directions of the words (e, se, s, sw, w, nw, n, ne) or all")
+	overlaps := flags.Bool("overlaps", false, "allow words to share letters")
+	answers := flags.Bool("answers", false, "also render the answer key")
+	compact := flags.Bool("compact", false, "compact rendering")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if !isSizeValid(*rows) || !isSizeValid(*cols) {
+		return nil, fmt.Errorf("invalid dimensions")
+	}
+
+	if !isSeedValid(*seed) {
+		return nil, fmt.Errorf("invalid word search seed")
+	}
+
+	if *count < 1 {
+		return nil, fmt.Errorf("invalid number of words")
+	}
+
+	var words []string
+	if *wordsFile != "" {
+		content, err := os.ReadFile(*wordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read words: %w", err)
+		}
+		words = strings.Fields(string(content))
+	}
+
+	var wordDirections []wordsearch.Direction
+	if *directions != "all" {
+		for _, name := range strings.Split(*directions, ",") {
+			direction, err := wordsearch.ParseDirection(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			wordDirections = append(wordDirections, direction)
+		}
+	}
+
+	var render renderer.Renderer = renderer.NewStandardRenderer()
+	if *compact {
+		render = renderer.NewCompactRenderer()
+	}
+
+	return &wordSearchParseResult{
+		Config: wordsearch.WordSearchConfig{
+			Rows:       *rows,
+			Cols:       *cols,
+			Words:      words,
+			Count:      *count,
+			MinLength:  *minLength,
+			MaxLength:  *maxLength,
+			Directions: wordDirections,
+			Overlaps:   *overlaps,
+			Seed:       *seed,
+		},
+		AnswerKey: *answers,
+		Renderer:  render,
+	}, nil
+}
+
+func generateWordSearch(parseResult *wordSearchParseResult) error {
+	fmt.Println("Generating word search...")
+	config := parseResult.Config
+	if len(config.Words) == 0 {
+		config.WordDict = dictionary.NewWordDictionary()
+	}
+	result, err := wordsearch.NewWordSearch(config)
+	if err != nil {
+		return err
+	}
+	ws := result.WordSearch
+
+	fmt.Printf("\n%s\n\n", parseResult.Renderer.RenderWordSearch(ws, false))
+	words := []string{}
+	for _, word := range ws.Words() {
+		words = append(words, strings.ToUpper(word.Word))
+	}
+	fmt.Printf("Words: %s\n", strings.Join(words, ", "))
+	if parseResult.AnswerKey {
+		fmt.Printf("\nAnswer key:\n\n%s\n", parseResult.Renderer.RenderWordSearch(ws, true))
+	}
+	fmt.Println("\nWord search generated successfully!")
+	fmt.Printf("Seed: %d\n", result.Seed)
+	return nil
+}
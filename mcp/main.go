@@ -2,24 +2,59 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"runtime"
 
 	"github.com/ahboujelben/go-crossword/cli/renderer"
 	"github.com/ahboujelben/go-crossword/modules/crossword"
 	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/fillin"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type Input struct {
-	Rows int `json:"rows" jsonschema:"the number of rows in the crossword"`
-	Cols int `json:"cols" jsonschema:"the number of columns in the crossword"`
+	Rows    int      `json:"rows" jsonschema:"the number of rows in the crossword"`
+	Cols    int      `json:"cols" jsonschema:"the number of columns in the crossword"`
+	Mini    bool     `json:"mini,omitempty" jsonschema:"generate a dense mini puzzle of at most 6 rows and columns"`
+	Blocks  int      `json:"blocks,omitempty" jsonschema:"the number of blank corner squares in a mini puzzle, from 0 to 4"`
+	Words   []string `json:"words,omitempty" jsonschema:"words to build a freestyle criss-cross from instead of a dense grid - rows and cols then optionally bound its size"`
+	FillIn  bool     `json:"fillIn,omitempty" jsonschema:"generate a fill-in puzzle: the unsolved grid shows a few given letters and the answers are listed by length instead of being clued"`
+	Exclude []string `json:"exclude,omitempty" jsonschema:"words the crossword must not contain, such as recently used answers"`
+	Require []string `json:"require,omitempty" jsonschema:"words the crossword must contain, placed wherever they fit"`
+	// MinWordLength and MaxWordLength bound the length of the words of the
+	// layout, zero meaning no bound.
+	MinWordLength int    `json:"minWordLength,omitempty" jsonschema:"the shortest word of the crossword - 0 for no bound"`
+	MaxWordLength int    `json:"maxWordLength,omitempty" jsonschema:"the longest word of the crossword - 0 for no bound"`
+	Outline       string `json:"outline,omitempty" jsonschema:"the outline of a non-rectangular grid: circle, diamond, heart or star - the squares outside it are shaded with ░░░ in the rendered grids"`
+	Density       string `json:"density,omitempty" jsonschema:"the range of the proportion of blank squares in percents, such as 15-20 - low densities make large grids hard to fill"`
+	// MaxUnchecked bounds the percentage of unchecked letters when set, 0
+	// requiring every letter to be checked.
+	MaxUnchecked *int `json:"maxUnchecked,omitempty" jsonschema:"the highest percentage of letters belonging to a single word, from 0 to 100 - 0 checks every letter, and there is no limit when omitted"`
+	// Difficulty restricts the words to the most common ones, ranked by the
+	// embedded frequencies or the frequency file named by
+	// $GO_CROSSWORD_FREQUENCIES.
+	Difficulty string `json:"difficulty,omitempty" jsonschema:"restrict the words to the most common ones of a level: easy, medium or hard"`
 }
 
 type Output struct {
-	UnsolvedCrossword string `json:"unsolvedCrossword" jsonschema:"the crossword grid without the solution - to be printed as is"`
-	SolvedCrossword   string `json:"solvedCrossword" jsonschema:"the crossword grid with the solution - to be printed as is"`
-	RowWords          []Word `json:"rowWords" jsonschema:"the list of row words in the solved crossword"`
-	ColumnWords       []Word `json:"columnWords" jsonschema:"the list of column words in the solved crossword"`
+	UnsolvedCrossword string               `json:"unsolvedCrossword" jsonschema:"the crossword grid without the solution - to be printed as is"`
+	SolvedCrossword   string               `json:"solvedCrossword" jsonschema:"the crossword grid with the solution - to be printed as is"`
+	RowWords          []Word               `json:"rowWords" jsonschema:"the list of row words in the solved crossword"`
+	ColumnWords       []Word               `json:"columnWords" jsonschema:"the list of column words in the solved crossword"`
+	FillInWords       []WordGroup          `json:"fillInWords,omitempty" jsonschema:"the answers of a fill-in puzzle grouped by length - to be shown to the user instead of clues"`
+	Difficulty        string               `json:"difficulty" jsonschema:"the difficulty rating of the crossword: easy, medium or hard"`
+	DifficultyScore   float64              `json:"difficultyScore" jsonschema:"the difficulty score of the crossword, from 0 for the easiest to 1 for the hardest"`
+	DifficultyRanked  bool                 `json:"difficultyRanked" jsonschema:"whether the difficulty weighs the rarity of the answers, which needs the words to be ranked by frequency - otherwise it only reflects the shape of the grid"`
+	CommonWords       int                  `json:"commonWords,omitempty" jsonschema:"the number of most common words the grid was filled with, more than the requested level when it couldn't be filled with fewer - 0 for all the words"`
+	Density           string               `json:"density,omitempty" jsonschema:"the range of the proportion of blank squares the grid was generated within in percents, higher than the requested one when the grid couldn't be filled with fewer blank squares"`
+	Stats             crossword.Statistics `json:"stats" jsonschema:"statistics of the solved crossword: word counts and lengths, blanks, checked letters, letter counts and Scrabble score"`
+}
+
+type WordGroup struct {
+	Length int      `json:"length"`
+	Words  []string `json:"words"`
 }
 
 type WordRef interface {
@@ -42,8 +77,45 @@ func newWord(word WordRef) Word {
 	}
 }
 
+// frequenciesEnv is the environment variable naming a frequency file ranking
+// the words of the dictionary instead of the embedded frequencies
+const frequenciesEnv = "GO_CROSSWORD_FREQUENCIES"
+
+func loadFrequencies(wordDict dictionary.WordDictionary, path string) (dictionary.WordDictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return wordDict, err
+	}
+	defer file.Close()
+	return wordDict.WithFrequencies(file)
+}
+
 func isSizeValid(size int) bool {
-	return size >= 3 && size <= 15
+	return size >= 3 && size <= 25
+}
+
+func isMiniSizeValid(size int) bool {
+	return size >= 3 && size <= 6
+}
+
+func newErrorResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+		IsError: true,
+	}
+}
+
+func emptyOutput() Output {
+	return Output{
+		UnsolvedCrossword: "",
+		SolvedCrossword:   "",
+		RowWords:          []Word{},
+		ColumnWords:       []Word{},
+	}
 }
 
 func GenerateCrossword(ctx context.Context, req *mcp.CallToolRequest, input Input) (
@@ -52,32 +124,113 @@ func GenerateCrossword(ctx context.Context, req *mcp.CallToolRequest, input Inpu
 	error,
 ) {
 	// Validate input dimensions
-	if !isSizeValid(input.Rows) || !isSizeValid(input.Cols) {
-		return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: "rows and cols must be between 3 and 15 inclusive",
-					},
-				},
-				IsError: true,
-			}, Output{
-				UnsolvedCrossword: "",
-				SolvedCrossword:   "",
-				RowWords:          []Word{},
-				ColumnWords:       []Word{},
-			}, nil
-	}
-
-	result := crossword.NewCrossword(crossword.CrosswordConfig{
-		Rows:     input.Rows,
-		Cols:     input.Cols,
-		Threads:  100,
-		WordDict: dictionary.NewWordDictionary(),
-	})
+	if len(input.Words) > 0 {
+		if (input.Rows != 0 && !isSizeValid(input.Rows)) || (input.Cols != 0 && !isSizeValid(input.Cols)) {
+			return newErrorResult("rows and cols must be between 3 and 25 inclusive"), emptyOutput(), nil
+		}
+	} else if !isSizeValid(input.Rows) || !isSizeValid(input.Cols) {
+		return newErrorResult("rows and cols must be between 3 and 25 inclusive"), emptyOutput(), nil
+	}
+
+	if input.Mini && (!isMiniSizeValid(input.Rows) || !isMiniSizeValid(input.Cols)) {
+		return newErrorResult("rows and cols of a mini puzzle must be between 3 and 6 inclusive"), emptyOutput(), nil
+	}
+
+	if input.Blocks < 0 || input.Blocks > 4 {
+		return newErrorResult("blocks must be between 0 and 4 inclusive"), emptyOutput(), nil
+	}
+
+	if input.MinWordLength < 0 || input.MaxWordLength < 0 ||
+		(input.MaxWordLength > 0 && input.MaxWordLength < max(input.MinWordLength, 2)) {
+		return newErrorResult("word lengths can't be negative, and maxWordLength must be 0 or at least 2 and minWordLength"), emptyOutput(), nil
+	}
+
+	var outline crossword.Outline
+	if input.Outline != "" {
+		var err error
+		if outline, err = crossword.OutlineByName(input.Outline); err != nil {
+			return newErrorResult("outline must be circle, diamond, heart or star"), emptyOutput(), nil
+		}
+	}
+
+	var minDensity, maxDensity float64
+	if input.Density != "" {
+		var err error
+		if minDensity, maxDensity, err = crossword.ParseDensity(input.Density); err != nil {
+			return newErrorResult("density must be a range of percents, such as 15-20"), emptyOutput(), nil
+		}
+	}
+
+	if input.MaxUnchecked != nil && (*input.MaxUnchecked < 0 || *input.MaxUnchecked > 100) {
+		return newErrorResult("maxUnchecked must be between 0 and 100 inclusive"), emptyOutput(), nil
+	}
+
+	commonWords := 0
+	if input.Difficulty != "" {
+		var err error
+		if commonWords, err = dictionary.LevelWords(input.Difficulty); err != nil {
+			return newErrorResult("difficulty must be easy, medium or hard"), emptyOutput(), nil
+		}
+	}
+
+	wordDict := dictionary.NewWordDictionary()
+	if frequencies := os.Getenv(frequenciesEnv); frequencies != "" {
+		var err error
+		if wordDict, err = loadFrequencies(wordDict, frequencies); err != nil {
+			return nil, Output{}, err
+		}
+	}
+
+	config := crossword.CrosswordConfig{
+		Rows:          input.Rows,
+		Cols:          input.Cols,
+		Threads:       runtime.NumCPU(),
+		WordDict:      wordDict,
+		Mini:          input.Mini,
+		MiniBlocks:    input.Blocks,
+		Words:         input.Words,
+		Exclude:       input.Exclude,
+		Require:       input.Require,
+		Outline:       outline,
+		MinWordLength: input.MinWordLength,
+		MaxWordLength: input.MaxWordLength,
+		MinDensity:    minDensity,
+		MaxDensity:    maxDensity,
+		CommonWords:   commonWords,
+	}
+	if input.MaxUnchecked != nil {
+		config.LimitUnchecked = true
+		config.MaxUncheckedRatio = float64(*input.MaxUnchecked) / 100
+	}
+	// crosswords whose fill-in puzzle may have several solutions are
+	// regenerated
+	if input.FillIn {
+		config.Accept = fillin.Accept
+	}
+	result, err := crossword.NewCrossword(config)
+	if err != nil {
+		return nil, Output{}, err
+	}
 
 	c := result.Crossword
 
+	density := ""
+	if input.Density != "" {
+		density = fmt.Sprintf("%.0f-%.0f", 100*result.MinDensity, 100*result.MaxDensity)
+	}
+
 	unsolvedCrossword := renderer.NewStandardRenderer().RenderCrossword(c, false)
+	var fillInWords []WordGroup
+	if input.FillIn {
+		f, err := fillin.NewFillIn(c)
+		if err != nil {
+			return nil, Output{}, err
+		}
+		unsolvedCrossword = renderer.NewStandardRenderer().RenderCrossword(f.Puzzle(), true)
+		for _, group := range f.Words() {
+			fillInWords = append(fillInWords, WordGroup{Length: group.Length, Words: group.Words})
+		}
+	}
 	solvedCrossword := renderer.NewStandardRenderer().RenderCrossword(c, true)
 
 	rowWords := []Word{}
@@ -96,6 +249,13 @@ func GenerateCrossword(ctx context.Context, req *mcp.CallToolRequest, input Inpu
 			SolvedCrossword:   solvedCrossword,
 			RowWords:          rowWords,
 			ColumnWords:       columnWords,
+			FillInWords:       fillInWords,
+			Difficulty:        result.Difficulty.Label(),
+			DifficultyScore:   result.Difficulty.Score,
+			DifficultyRanked:  result.Difficulty.Ranked,
+			CommonWords:       result.CommonWords,
+			Density:           density,
+			Stats:             crossword.Stats(c),
 		},
 		nil
 }
@@ -114,6 +274,8 @@ IMPORTANT: When a user requests a crossword, always:
 s. Prefix each clue for down words with its position in the grid in this format (Col: y, Row: x).
 5. Only reveal the solved solution and the words when explicitly requested or if the user gives up.
 
+For fill-in puzzles, display the fillInWords grouped by length instead of generating clues.
+
 The clues should be presented in a clear format with numbered clues for Across and Down.
 `
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -38,17 +39,188 @@ func TestGenerateCrossword(t *testing.T) {
 		if len(output.ColumnWords) == 0 {
 			t.Error("ColumnWords should not be empty for a valid crossword")
 		}
+
+		if words := len(output.RowWords) + len(output.ColumnWords); output.Stats.Words != words {
+			t.Errorf("Expected statistics of %d words, but got %d", words, output.Stats.Words)
+		}
+
+		if output.Difficulty == "" || output.DifficultyScore <= 0 || output.DifficultyScore > 1 {
+			t.Errorf("Expected a difficulty rating, but got %q (%.2f)", output.Difficulty, output.DifficultyScore)
+		}
+
+		if !output.DifficultyRanked {
+			t.Error("Expected the difficulty to weigh the rarity of the answers")
+		}
+	})
+
+	t.Run("mini input generates a dense crossword", func(t *testing.T) {
+		input := Input{Rows: 4, Cols: 4, Mini: true}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		if len(output.RowWords) != 4 || len(output.ColumnWords) != 4 {
+			t.Errorf("Expected 4 row and column words, but got %d and %d", len(output.RowWords), len(output.ColumnWords))
+		}
+	})
+
+	t.Run("words input generates a criss-cross", func(t *testing.T) {
+		input := Input{Words: []string{"planet", "orbit", "comet", "star"}}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		if words := len(output.RowWords) + len(output.ColumnWords); words != len(input.Words) {
+			t.Errorf("Expected %d words, but got %d", len(input.Words), words)
+		}
+	})
+
+	t.Run("fill-in input lists the answers by length", func(t *testing.T) {
+		input := Input{Rows: 7, Cols: 7, FillIn: true}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		answers := 0
+		for _, group := range output.FillInWords {
+			answers += len(group.Words)
+		}
+		if words := len(output.RowWords) + len(output.ColumnWords); answers != words {
+			t.Errorf("Expected %d fill-in answers, but got %d", words, answers)
+		}
+	})
+
+	t.Run("exclude and require inputs shape the answers", func(t *testing.T) {
+		input := Input{Rows: 9, Cols: 9, Exclude: []string{"ease", "area"}, Require: []string{"gopher"}}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		answers := map[string]bool{}
+		for _, word := range append(output.RowWords, output.ColumnWords...) {
+			answers[word.Value] = true
+		}
+		if !answers["gopher"] {
+			t.Errorf("Expected the required word in the crossword")
+		}
+		if answers["ease"] || answers["area"] {
+			t.Errorf("Expected no excluded word in the crossword")
+		}
+	})
+
+	t.Run("word length inputs bound the words", func(t *testing.T) {
+		input := Input{Rows: 9, Cols: 9, MinWordLength: 3, MaxWordLength: 7}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		for _, word := range append(output.RowWords, output.ColumnWords...) {
+			if len(word.Value) < 3 || len(word.Value) > 7 {
+				t.Errorf("Expected words of 3 to 7 letters, but got %q", word.Value)
+			}
+		}
+	})
+
+	t.Run("outline input masks the squares outside it", func(t *testing.T) {
+		input := Input{Rows: 11, Cols: 11, Outline: "diamond"}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		// the diamond leaves out 15 squares in every corner
+		if output.Stats.Masked != 60 {
+			t.Errorf("Expected 60 masked squares, but got %d", output.Stats.Masked)
+		}
+	})
+
+	t.Run("density input bounds the blank squares", func(t *testing.T) {
+		input := Input{Rows: 9, Cols: 9, Density: "35-40"}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		if output.Stats.BlankRatio < 0.35 || output.Stats.BlankRatio > 0.4 {
+			t.Errorf("Expected 35%% to 40%% of blank squares, but got %.0f%%", 100*output.Stats.BlankRatio)
+		}
+
+		if output.Density != "35-40" {
+			t.Errorf("Expected a density of 35-40, but got %q", output.Density)
+		}
+	})
+
+	t.Run("max unchecked input bounds the unchecked letters", func(t *testing.T) {
+		maxUnchecked := 65
+		input := Input{Rows: 9, Cols: 9, MaxUnchecked: &maxUnchecked}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		letters := output.Stats.Checked + output.Stats.Unchecked
+		if 100*output.Stats.Unchecked > 65*letters {
+			t.Errorf("Expected at most 65%% of unchecked letters, but got %d of %d", output.Stats.Unchecked, letters)
+		}
+	})
+
+	t.Run("difficulty input restricts the words to common ones", func(t *testing.T) {
+		input := Input{Rows: 7, Cols: 7, Difficulty: "medium"}
+		_, output, err := GenerateCrossword(ctx, req, input)
+
+		if err != nil {
+			t.Fatalf("GenerateCrossword() returned an unexpected error: %v", err)
+		}
+
+		if output.CommonWords != 10000 && output.CommonWords != 0 {
+			t.Errorf("Expected the 10000 most common words or all of them, but got %d", output.CommonWords)
+		}
+	})
+
+	t.Run("frequency file replaces the embedded frequencies", func(t *testing.T) {
+		t.Setenv(frequenciesEnv, filepath.Join(t.TempDir(), "missing.txt"))
+
+		input := Input{Rows: 5, Cols: 5}
+		_, _, err := GenerateCrossword(ctx, req, input)
+
+		if err == nil {
+			t.Error("Expected an error for a missing frequency file")
+		}
 	})
 
 	t.Run("invalid input returns an error result", func(t *testing.T) {
+		tooManyUnchecked := 101
 		testCases := []struct {
-			name  string
-			input Input
+			name        string
+			input       Input
+			expectedMsg string
 		}{
-			{"rows too small", Input{Rows: 2, Cols: 5}},
-			{"cols too small", Input{Rows: 5, Cols: 1}},
-			{"rows too large", Input{Rows: 16, Cols: 5}},
-			{"cols too large", Input{Rows: 5, Cols: 20}},
+			{"rows too small", Input{Rows: 2, Cols: 5}, "rows and cols must be between 3 and 25 inclusive"},
+			{"cols too small", Input{Rows: 5, Cols: 1}, "rows and cols must be between 3 and 25 inclusive"},
+			{"rows too large", Input{Rows: 26, Cols: 5}, "rows and cols must be between 3 and 25 inclusive"},
+			{"cols too large", Input{Rows: 5, Cols: 30}, "rows and cols must be between 3 and 25 inclusive"},
+			{"mini too large", Input{Rows: 7, Cols: 5, Mini: true}, "rows and cols of a mini puzzle must be between 3 and 6 inclusive"},
+			{"words with rows too large", Input{Rows: 26, Words: []string{"planet", "orbit"}}, "rows and cols must be between 3 and 25 inclusive"},
+			{"too many blocks", Input{Rows: 5, Cols: 5, Mini: true, Blocks: 5}, "blocks must be between 0 and 4 inclusive"},
+			{"negative min word length", Input{Rows: 5, Cols: 5, MinWordLength: -1}, "word lengths can't be negative, and maxWordLength must be 0 or at least 2 and minWordLength"},
+			{"max word length below min", Input{Rows: 5, Cols: 5, MinWordLength: 4, MaxWordLength: 3}, "word lengths can't be negative, and maxWordLength must be 0 or at least 2 and minWordLength"},
+			{"unknown outline", Input{Rows: 5, Cols: 5, Outline: "square"}, "outline must be circle, diamond, heart or star"},
+			{"invalid density", Input{Rows: 5, Cols: 5, Density: "20"}, "density must be a range of percents, such as 15-20"},
+			{"max unchecked too large", Input{Rows: 5, Cols: 5, MaxUnchecked: &tooManyUnchecked}, "maxUnchecked must be between 0 and 100 inclusive"},
+			{"unknown difficulty", Input{Rows: 5, Cols: 5, Difficulty: "kids"}, "difficulty must be easy, medium or hard"},
 		}
 
 		for _, tc := range testCases {
@@ -67,7 +239,6 @@ func TestGenerateCrossword(t *testing.T) {
 					t.Error("Expected result.IsError to be true for invalid input")
 				}
 
-				expectedMsg := "rows and cols must be between 3 and 15 inclusive"
 				if len(result.Content) == 0 {
 					t.Fatal("Expected result.Content to have at least one item")
 				}
@@ -76,8 +247,8 @@ func TestGenerateCrossword(t *testing.T) {
 					t.Fatal("Expected result.Content[0] to be of type *mcp.TextContent")
 				}
 
-				if textContent.Text != expectedMsg {
-					t.Errorf("Expected error message '%s', but got '%s'", expectedMsg, textContent.Text)
+				if textContent.Text != tc.expectedMsg {
+					t.Errorf("Expected error message '%s', but got '%s'", tc.expectedMsg, textContent.Text)
 				}
 
 				if output.UnsolvedCrossword != "" || output.SolvedCrossword != "" || len(output.RowWords) > 0 || len(output.ColumnWords) > 0 {
@@ -0,0 +1,197 @@
+package codeword
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// ErrUnsolvable is returned by NewCodeword when the crossword has words missing
+// from the dictionary, so that the codeword has no solution.
+var ErrUnsolvable = errors.New("the crossword has words missing from the dictionary")
+
+type CodewordConfig struct {
+	// Reveals is the number of starter letters given to the solver. More
+	// letters are revealed when needed to make the solution unique.
+	Reveals  int
+	WordDict dictionary.WordSource
+	Seed     int64
+}
+
+// Codeword is a filled crossword whose letters are replaced by numbers, the
+// same number standing for the same letter everywhere in the grid.
+type Codeword struct {
+	grid     *crossword.Crossword
+	numbers  [26]int
+	letters  []byte
+	revealed []bool
+}
+
+// NewCodeword numbers the letters of a filled crossword from 1 in a random
+// order and reveals starter letters until the codeword has a unique solution
+// among the words of the dictionary. Only grids of lowercase letters can be
+// numbered.
+func NewCodeword(c *crossword.Crossword, config CodewordConfig) (*Codeword, error) {
+	seed := config.Seed
+	for seed == 0 {
+		seed = rand.Int63()
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	used := []byte{}
+	for letter := crossword.CrosswordLetter(c); letter != nil; letter = letter.Next() {
+		if letter.IsBlank() || letter.IsEmpty() {
+			continue
+		}
+		if !isLetter(letter.GetValue()) {
+			return nil, fmt.Errorf("only letters can be numbered, the grid holds %q", letter.GetValue())
+		}
+		if !slices.Contains(used, letter.GetValue()) {
+			used = append(used, letter.GetValue())
+		}
+	}
+	slices.Sort(used)
+	random.Shuffle(len(used), func(i, j int) {
+		used[i], used[j] = used[j], used[i]
+	})
+
+	cw := &Codeword{
+		grid:     c,
+		letters:  append([]byte{0}, used...),
+		revealed: make([]bool, len(used)+1),
+	}
+	for i, letter := range used {
+		cw.numbers[letter-'a'] = i + 1
+	}
+
+	for _, number := range random.Perm(cw.Size())[:min(config.Reveals, cw.Size())] {
+		cw.revealed[number+1] = true
+	}
+	// a letter outside of any word can only be given
+	for _, number := range cw.unchecked() {
+		cw.revealed[number] = true
+	}
+
+	for {
+		solutions, complete := newSolver(cw, config.WordDict).solve(2)
+		switch {
+		case complete && len(solutions) == 0:
+			return nil, ErrUnsolvable
+		case complete && len(solutions) == 1:
+			return cw, nil
+		}
+		cw.revealed[cw.disambiguation(solutions)] = true
+	}
+}
+
+// Grid returns the solution of the codeword.
+func (cw *Codeword) Grid() *crossword.Crossword {
+	return cw.grid
+}
+
+// Size returns the number of distinct letters of the codeword, which are
+// numbered from 1 to Size.
+func (cw *Codeword) Size() int {
+	return len(cw.letters) - 1
+}
+
+// Number returns the number standing for the letter, or 0 if the letter isn't
+// in the grid.
+func (cw *Codeword) Number(letter byte) int {
+	if !isLetter(letter) {
+		return 0
+	}
+	return cw.numbers[letter-'a']
+}
+
+// isLetter reports whether the byte is a lowercase letter, which are the only
+// ones numbered.
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
+
+// Letter returns the letter the number stands for.
+func (cw *Codeword) Letter(number int) byte {
+	return cw.letters[number]
+}
+
+// NumberAt returns the number of the square, or 0 for a blank square.
+func (cw *Codeword) NumberAt(row, column int) int {
+	letter := crossword.CrosswordLetterAt(cw.grid, row, column)
+	if letter.IsBlank() || letter.IsEmpty() {
+		return 0
+	}
+	return cw.Number(letter.GetValue())
+}
+
+// IsRevealed reports whether the letter of the number is given to the solver.
+func (cw *Codeword) IsRevealed(number int) bool {
+	return cw.revealed[number]
+}
+
+// Reveals returns the number of letters given to the solver.
+func (cw *Codeword) Reveals() int {
+	reveals := 0
+	for _, revealed := range cw.revealed {
+		if revealed {
+			reveals++
+		}
+	}
+	return reveals
+}
+
+// IsUnique reports whether the revealed letters lead to a single solution
+// among the words of the dictionary. It returns false when uniqueness can't
+// be established within the search budget of the solver.
+func (cw *Codeword) IsUnique(wordDict dictionary.WordSource) bool {
+	solutions, complete := newSolver(cw, wordDict).solve(2)
+	return complete && len(solutions) == 1
+}
+
+// unchecked returns the numbers of the letters not belonging to any word.
+func (cw *Codeword) unchecked() []int {
+	inWord := make([]bool, cw.Size()+1)
+	for word := crossword.Word(cw.grid); word != nil; word = word.Next() {
+		for _, letter := range word.GetValue() {
+			inWord[cw.Number(letter)] = true
+		}
+	}
+	unchecked := []int{}
+	for number := 1; number <= cw.Size(); number++ {
+		if !inWord[number] {
+			unchecked = append(unchecked, number)
+		}
+	}
+	return unchecked
+}
+
+// disambiguation returns the next number to reveal: one that two solutions
+// disagree on or, failing that, the hidden number appearing the most often in
+// the grid.
+func (cw *Codeword) disambiguation(solutions [][]byte) int {
+	if len(solutions) == 2 {
+		for number := 1; number <= cw.Size(); number++ {
+			if solutions[0][number] != solutions[1][number] {
+				return number
+			}
+		}
+	}
+
+	counts := make([]int, cw.Size()+1)
+	for letter := crossword.CrosswordLetter(cw.grid); letter != nil; letter = letter.Next() {
+		if number := cw.NumberAt(letter.Row(), letter.Column()); number != 0 {
+			counts[number]++
+		}
+	}
+	best := 0
+	for number := 1; number <= cw.Size(); number++ {
+		if !cw.revealed[number] && (best == 0 || counts[number] > counts[best]) {
+			best = number
+		}
+	}
+	return best
+}
@@ -0,0 +1,74 @@
+package codeword_test
+
+import (
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/codeword"
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCodeword(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     9,
+		Cols:     9,
+		Threads:  4,
+		WordDict: wordDict,
+	})
+	assert.NoError(t, err)
+
+	cw, err := codeword.NewCodeword(result.Crossword, codeword.CodewordConfig{
+		Reveals:  2,
+		WordDict: wordDict,
+		Seed:     result.Seed,
+	})
+	assert.NoError(t, err)
+	assert.True(t, cw.IsUnique(wordDict))
+	assert.GreaterOrEqual(t, cw.Reveals(), 2)
+	assert.Zero(t, cw.Number(0))
+	assert.Zero(t, cw.Number('A'))
+
+	numbers := map[int]byte{}
+	for letter := crossword.CrosswordLetter(result.Crossword); letter != nil; letter = letter.Next() {
+		number := cw.NumberAt(letter.Row(), letter.Column())
+		if letter.IsBlank() {
+			assert.Zero(t, number)
+			continue
+		}
+		assert.GreaterOrEqual(t, number, 1)
+		assert.LessOrEqual(t, number, cw.Size())
+		assert.Equal(t, letter.GetValue(), cw.Letter(number))
+		if previous, exists := numbers[number]; exists {
+			assert.Equal(t, previous, letter.GetValue())
+		}
+		numbers[number] = letter.GetValue()
+	}
+	assert.Len(t, numbers, cw.Size())
+
+	replayed, err := codeword.NewCodeword(result.Crossword, codeword.CodewordConfig{
+		Reveals:  2,
+		WordDict: wordDict,
+		Seed:     result.Seed,
+	})
+	assert.NoError(t, err)
+	for number := 1; number <= cw.Size(); number++ {
+		assert.Equal(t, cw.Letter(number), replayed.Letter(number))
+		assert.Equal(t, cw.IsRevealed(number), replayed.IsRevealed(number))
+	}
+}
+
+func TestNewCodewordUnsolvable(t *testing.T) {
+	c, err := crossword.ParseCrossword("xq\nqx")
+	assert.NoError(t, err)
+	_, err = codeword.NewCodeword(c, codeword.CodewordConfig{WordDict: dictionary.NewWordDictionary()})
+	assert.ErrorIs(t, err, codeword.ErrUnsolvable)
+}
+
+func TestNewCodewordOfNonLetters(t *testing.T) {
+	c, err := crossword.ParseCrossword("12\n34")
+	assert.NoError(t, err)
+	_, err = codeword.NewCodeword(c, codeword.CodewordConfig{WordDict: dictionary.NewWordDictionary()})
+	assert.Error(t, err)
+}
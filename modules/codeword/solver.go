@@ -0,0 +1,155 @@
+package codeword
+
+import (
+	"slices"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// maxSolverNodes bounds the number of partial solutions explored by the
+// solver, beyond which uniqueness is considered unproven.
+const maxSolverNodes = 20000
+
+// solver finds the letters the numbers of a codeword can stand for, so that
+// every word of the grid is in the dictionary and different numbers stand for
+// different letters. It fills one word at a time, always the one with the
+// fewest candidates left.
+type solver struct {
+	wordDict dictionary.WordSource
+	words    [][]int
+	letters  []byte
+	numbers  [26]int
+	nodes    int
+}
+
+func newSolver(cw *Codeword, wordDict dictionary.WordSource) *solver {
+	s := &solver{
+		wordDict: wordDict,
+		letters:  make([]byte, cw.Size()+1),
+	}
+	for word := crossword.Word(cw.grid); word != nil; word = word.Next() {
+		numbers := []int{}
+		for _, letter := range word.GetValue() {
+			numbers = append(numbers, cw.Number(letter))
+		}
+		s.words = append(s.words, numbers)
+	}
+	for number := 1; number <= cw.Size(); number++ {
+		if cw.IsRevealed(number) {
+			s.assign(number, cw.Letter(number))
+		}
+	}
+	return s
+}
+
+// solve returns up to limit solutions, each one giving the letter of every
+// number, and whether the search was exhaustive.
+func (s *solver) solve(limit int) ([][]byte, bool) {
+	solutions := [][]byte{}
+	complete := s.search(limit, &solutions)
+	return solutions, complete
+}
+
+func (s *solver) search(limit int, solutions *[][]byte) bool {
+	s.nodes++
+	if s.nodes > maxSolverNodes {
+		return false
+	}
+
+	next, candidates := -1, []string(nil)
+	for i, word := range s.words {
+		pattern := s.pattern(word)
+		if !slices.Contains(pattern, 0) {
+			if !s.wordDict.Contains(string(pattern)) {
+				return true
+			}
+			continue
+		}
+		wordCandidates := s.candidates(word, pattern)
+		if next == -1 || len(wordCandidates) < len(candidates) {
+			next, candidates = i, wordCandidates
+		}
+		if len(candidates) == 0 {
+			return true
+		}
+	}
+	if next == -1 {
+		*solutions = append(*solutions, slices.Clone(s.letters))
+		return true
+	}
+
+	word := s.words[next]
+	for _, candidate := range candidates {
+		assigned := []int{}
+		for i, number := range word {
+			if s.letters[number] == 0 {
+				s.assign(number, candidate[i])
+				assigned = append(assigned, number)
+			}
+		}
+		complete := s.search(limit, solutions)
+		for _, number := range assigned {
+			s.unassign(number)
+		}
+		if !complete {
+			return false
+		}
+		if len(*solutions) >= limit {
+			return true
+		}
+	}
+	return true
+}
+
+// pattern returns the known letters of the word, 0 standing for unknown ones.
+func (s *solver) pattern(word []int) []byte {
+	pattern := make([]byte, len(word))
+	for i, number := range word {
+		pattern[i] = s.letters[number]
+	}
+	return pattern
+}
+
+// candidates returns the dictionary words matching the known letters of the
+// word whose other letters are consistent with its numbers: the same number
+// for the same letter and unknown numbers for letters no other number stands
+// for.
+func (s *solver) candidates(word []int, pattern []byte) []string {
+	candidates := []string{}
+	for _, index := range s.wordDict.Candidates(pattern) {
+		candidate := s.wordDict.Word(index)
+		if s.consistent(word, candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+func (s *solver) consistent(word []int, candidate string) bool {
+	for i, number := range word {
+		if s.letters[number] != 0 {
+			continue
+		}
+		letter := candidate[i]
+		if s.numbers[letter-'a'] != 0 {
+			return false
+		}
+		for j := range i {
+			if (word[j] == number) != (candidate[j] == letter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *solver) assign(number int, letter byte) {
+	s.letters[number] = letter
+	s.numbers[letter-'a'] = number
+}
+
+func (s *solver) unassign(number int) {
+	s.numbers[s.letters[number]-'a'] = 0
+	s.letters[number] = 0
+}
@@ -0,0 +1,133 @@
+package crossnumber
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+)
+
+type CrossNumberConfig struct {
+	// Rows and Cols must be between 3 and MaxDigits, so that every entry of
+	// the grid fits in the dictionary.
+	Rows    int
+	Cols    int
+	Threads int
+	// Properties are the properties the entries of the grid are clued by.
+	// DefaultProperties are used when nil.
+	Properties []Property
+	// Shaper lays out the blank squares of the grid. ClassicShaper is used
+	// when nil.
+	Shaper  crossword.Shaper
+	Seed    int64
+	Timeout time.Duration
+}
+
+// Clue is the clue of an entry of a cross-number, numbered like the clues of
+// a crossword.
+type Clue struct {
+	Number int
+	Across bool
+	Row    int
+	Column int
+	Answer string
+	Text   string
+}
+
+// CrossNumber is a crossword whose entries are numbers, each one clued by one
+// of its arithmetic properties.
+type CrossNumber struct {
+	grid  *crossword.Crossword
+	clues []Clue
+}
+
+type CrossNumberResult struct {
+	CrossNumber *CrossNumber
+	Seed        int64
+}
+
+// Grid returns the solution of the cross-number.
+func (cn *CrossNumber) Grid() *crossword.Crossword {
+	return cn.grid
+}
+
+// Clues returns the across clues followed by the down clues, in the order of
+// their numbers.
+func (cn *CrossNumber) Clues() []Clue {
+	return cn.clues
+}
+
+// NewCrossNumber fills a grid with numbers having the configured properties
+// and clues every entry by its most specific property. The returned seed
+// reproduces the cross-number.
+func NewCrossNumber(config CrossNumberConfig) (CrossNumberResult, error) {
+	if config.Rows < 3 || config.Rows > MaxDigits || config.Cols < 3 || config.Cols > MaxDigits {
+		return CrossNumberResult{}, fmt.Errorf("invalid dimensions %dx%d", config.Rows, config.Cols)
+	}
+	properties := config.Properties
+	if len(properties) == 0 {
+		properties = DefaultProperties()
+	}
+
+	numberDict := NewNumberDictionary(max(config.Rows, config.Cols), properties)
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     config.Rows,
+		Cols:     config.Cols,
+		Threads:  config.Threads,
+		WordDict: numberDict,
+		Seed:     config.Seed,
+		Shaper:   config.Shaper,
+		Timeout:  config.Timeout,
+	})
+	if err != nil {
+		return CrossNumberResult{}, err
+	}
+
+	cn := &CrossNumber{grid: result.Crossword}
+	numbers := clueNumbers(result.Crossword)
+	for word := crossword.RowWord(result.Crossword); word != nil; word = word.Next() {
+		cn.clues = append(cn.clues, newClue(numberDict, word.WordRef, numbers[word.GetPos()], true, word.Row(), word.Column()))
+	}
+	for word := crossword.ColumnWord(result.Crossword); word != nil; word = word.Next() {
+		cn.clues = append(cn.clues, newClue(numberDict, word.WordRef, numbers[word.GetPos()], false, word.Row(), word.Column()))
+	}
+	slices.SortStableFunc(cn.clues, func(a, b Clue) int {
+		if a.Across != b.Across {
+			if a.Across {
+				return -1
+			}
+			return 1
+		}
+		return a.Number - b.Number
+	})
+	return CrossNumberResult{CrossNumber: cn, Seed: result.Seed}, nil
+}
+
+// clueNumbers numbers the squares starting an entry, in reading order.
+func clueNumbers(c *crossword.Crossword) map[int]int {
+	starts := []int{}
+	for word := crossword.Word(c); word != nil; word = word.Next() {
+		if !slices.Contains(starts, word.GetPos()) {
+			starts = append(starts, word.GetPos())
+		}
+	}
+	slices.Sort(starts)
+	numbers := map[int]int{}
+	for i, start := range starts {
+		numbers[start] = i + 1
+	}
+	return numbers
+}
+
+func newClue(numberDict NumberDictionary, word *crossword.WordRef, number int, across bool, row, column int) Clue {
+	answer := string(word.GetValue())
+	return Clue{
+		Number: number,
+		Across: across,
+		Row:    row,
+		Column: column,
+		Answer: answer,
+		Text:   numberDict.Clue(answer),
+	}
+}
@@ -0,0 +1,72 @@
+package crossnumber_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/crossnumber"
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberDictionary(t *testing.T) {
+	numberDict := crossnumber.NewNumberDictionary(3, crossnumber.DefaultProperties())
+	assert.True(t, numberDict.Contains("13"))
+	assert.True(t, numberDict.Contains("144"))
+	assert.False(t, numberDict.Contains("14"))
+	assert.False(t, numberDict.Contains("7"))
+	assert.False(t, numberDict.Contains("1009"))
+
+	assert.Equal(t, "2 to the power 5", numberDict.Clue("32"))
+	assert.Equal(t, "cube of 5", numberDict.Clue("125"))
+	assert.Equal(t, "Fibonacci number F(12)", numberDict.Clue("144"))
+	assert.Equal(t, "square of 11", numberDict.Clue("121"))
+	assert.Equal(t, "sum of the numbers from 1 to 9", numberDict.Clue("45"))
+	assert.Equal(t, "prime number", numberDict.Clue("97"))
+	assert.Empty(t, numberDict.Clue("14"))
+
+	for _, index := range numberDict.Candidates([]byte{'9', 0}) {
+		assert.Equal(t, byte('9'), numberDict.Word(index)[0])
+	}
+
+	square, err := crossnumber.PropertyByName("square")
+	assert.NoError(t, err)
+	squares := crossnumber.NewNumberDictionary(2, []crossnumber.Property{square})
+	assert.Len(t, squares.Candidates([]byte{0, 0}), 6)
+
+	_, err = crossnumber.PropertyByName("unknown")
+	assert.Error(t, err)
+}
+
+func TestGenerateCrossNumber(t *testing.T) {
+	for size := 3; size <= crossnumber.MaxDigits; size++ {
+		t.Run(fmt.Sprintf("Size=%d", size), func(t *testing.T) {
+			result, err := crossnumber.NewCrossNumber(crossnumber.CrossNumberConfig{
+				Rows:    size,
+				Cols:    size,
+				Threads: 4,
+			})
+
+			assert.NoError(t, err)
+			cn := result.CrossNumber
+			assert.True(t, cn.Grid().IsFilled())
+			words := 0
+			for word := crossword.Word(cn.Grid()); word != nil; word = word.Next() {
+				words++
+			}
+			assert.Len(t, cn.Clues(), words)
+			for _, clue := range cn.Clues() {
+				assert.NotEqual(t, byte('0'), clue.Answer[0])
+				assert.NotEmpty(t, clue.Text)
+				_, err := strconv.Atoi(clue.Answer)
+				assert.NoError(t, err)
+				start := crossword.CrosswordLetterAt(cn.Grid(), clue.Row, clue.Column)
+				assert.Equal(t, clue.Answer[0], start.GetValue())
+			}
+		})
+	}
+
+	_, err := crossnumber.NewCrossNumber(crossnumber.CrossNumberConfig{Rows: 7, Cols: 5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package crossnumber
+
+import (
+	"strconv"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// MaxDigits is the number of digits of the longest numbers of a
+// NumberDictionary, beyond which listing the numbers having a property gets
+// too slow.
+const MaxDigits = 6
+
+// NumberDictionary is a word source of the numbers of 2 to maxDigits digits
+// having at least one of a set of properties, written without leading zeros.
+type NumberDictionary struct {
+	dictionary.WordDictionary
+	properties []Property
+	// counts[length][i] is the number of numbers of length digits having
+	// properties[i], the fewer the more specific the property.
+	counts map[int][]int
+}
+
+// NewNumberDictionary lists the numbers of 2 to maxDigits digits, at most
+// MaxDigits, having at least one of the properties.
+func NewNumberDictionary(maxDigits int, properties []Property) NumberDictionary {
+	d := NumberDictionary{
+		properties: properties,
+		counts:     map[int][]int{},
+	}
+
+	numbers := []string{}
+	low := 10
+	for length := 2; length <= min(maxDigits, MaxDigits); length++ {
+		d.counts[length] = make([]int, len(properties))
+		for n := low; n < low*10; n++ {
+			holds := false
+			for i, property := range properties {
+				if property.Holds(n) {
+					d.counts[length][i]++
+					holds = true
+				}
+			}
+			if holds {
+				numbers = append(numbers, strconv.Itoa(n))
+			}
+		}
+		low *= 10
+	}
+
+	d.WordDictionary = dictionary.NewWordDictionaryFromWords(numbers)
+	return d
+}
+
+// Clue describes the number by its most specific property. It returns an
+// empty string if the number isn't in the dictionary.
+func (d NumberDictionary) Clue(number string) string {
+	n, err := strconv.Atoi(number)
+	if err != nil || !d.Contains(number) {
+		return ""
+	}
+	best := -1
+	for i, property := range d.properties {
+		if property.Holds(n) && (best == -1 || d.counts[len(number)][i] < d.counts[len(number)][best]) {
+			best = i
+		}
+	}
+	return d.properties[best].Clue(n)
+}
@@ -0,0 +1,145 @@
+package crossnumber
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Property is an arithmetic property of numbers that cross-number clues are
+// made of.
+type Property struct {
+	Name  string
+	Holds func(n int) bool
+	// Clue describes a number having the property, such as "square of 12".
+	Clue func(n int) string
+}
+
+// DefaultProperties returns the built-in properties, from the most to the
+// least specific for numbers of a few digits.
+func DefaultProperties() []Property {
+	return []Property{
+		{
+			Name:  "power-of-two",
+			Holds: func(n int) bool { return n > 0 && n&(n-1) == 0 },
+			Clue: func(n int) string {
+				exponent := 0
+				for n > 1 {
+					n >>= 1
+					exponent++
+				}
+				return fmt.Sprintf("2 to the power %d", exponent)
+			},
+		},
+		{
+			Name:  "cube",
+			Holds: func(n int) bool { return cube(cubeRoot(n)) == n },
+			Clue:  func(n int) string { return fmt.Sprintf("cube of %d", cubeRoot(n)) },
+		},
+		{
+			Name:  "fibonacci",
+			Holds: func(n int) bool { return fibonacciIndex(n) > 0 },
+			Clue:  func(n int) string { return fmt.Sprintf("Fibonacci number F(%d)", fibonacciIndex(n)) },
+		},
+		{
+			Name:  "square",
+			Holds: func(n int) bool { root := squareRoot(n); return root*root == n },
+			Clue:  func(n int) string { return fmt.Sprintf("square of %d", squareRoot(n)) },
+		},
+		{
+			Name: "triangular",
+			Holds: func(n int) bool {
+				k := squareRoot(2 * n)
+				return k*(k+1)/2 == n
+			},
+			Clue: func(n int) string { return fmt.Sprintf("sum of the numbers from 1 to %d", squareRoot(2*n)) },
+		},
+		{
+			Name:  "palindrome",
+			Holds: isPalindrome,
+			Clue:  func(n int) string { return "palindrome" },
+		},
+		{
+			Name:  "prime",
+			Holds: isPrime,
+			Clue:  func(n int) string { return "prime number" },
+		},
+	}
+}
+
+// PropertyByName returns the built-in property registered under the given
+// name.
+func PropertyByName(name string) (Property, error) {
+	names := []string{}
+	for _, property := range DefaultProperties() {
+		if property.Name == name {
+			return property, nil
+		}
+		names = append(names, property.Name)
+	}
+	return Property{}, fmt.Errorf("unknown property %q (available: %v)", name, names)
+}
+
+func squareRoot(n int) int {
+	root := int(math.Sqrt(float64(n)))
+	for root*root > n {
+		root--
+	}
+	for (root+1)*(root+1) <= n {
+		root++
+	}
+	return root
+}
+
+func cube(n int) int {
+	return n * n * n
+}
+
+func cubeRoot(n int) int {
+	root := int(math.Cbrt(float64(n)))
+	for cube(root) > n {
+		root--
+	}
+	for cube(root+1) <= n {
+		root++
+	}
+	return root
+}
+
+// fibonacciIndex returns k such that n is the Fibonacci number F(k), F(1) and
+// F(2) being 1, or 0 if n isn't a Fibonacci number.
+func fibonacciIndex(n int) int {
+	previous, current := 1, 1
+	for k := 2; current <= n; k++ {
+		if current == n {
+			return k
+		}
+		previous, current = current, previous+current
+	}
+	return 0
+}
+
+func isPalindrome(n int) bool {
+	digits := strconv.Itoa(n)
+	for i := range len(digits) / 2 {
+		if digits[i] != digits[len(digits)-1-i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	if n%2 == 0 {
+		return n == 2
+	}
+	for divisor := 3; divisor*divisor <= n; divisor += 2 {
+		if n%divisor == 0 {
+			return false
+		}
+	}
+	return true
+}
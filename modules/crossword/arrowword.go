@@ -0,0 +1,73 @@
+package crossword
+
+import "math/rand"
+
+// Arrow tells which words a clue square of an arrowword points to. A clue
+// square holds the clue of the across word starting on its right and/or of the
+// down word starting below it.
+type Arrow byte
+
+const (
+	ArrowRight Arrow = 1 << iota
+	ArrowDown
+)
+
+// ArrowwordShaper lays out arrowwords, where the clues are written in blank
+// squares pointing to their answers. The first row and column are clue
+// squares, so that every word of the grid laid out by Inner (ClassicShaper
+// when nil) starts next to a clue square.
+type ArrowwordShaper struct {
+	Inner Shaper
+}
+
+func (s ArrowwordShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := make([]bool, rows*columns)
+	for i := range mask {
+		mask[i] = i < columns || i%columns == 0
+	}
+	if rows < 2 || columns < 2 {
+		return mask
+	}
+
+	inner := s.Inner
+	if inner == nil {
+		inner = ClassicShaper{}
+	}
+	for i, blank := range inner.Shape(rows-1, columns-1, random) {
+		mask[(i/(columns-1)+1)*columns+i%(columns-1)+1] = blank
+	}
+	return mask
+}
+
+// MarkClueSquares turns the crossword into an arrowword, each blank square
+// followed by a word becoming a clue square pointing to it.
+func (c *Crossword) MarkClueSquares() {
+	c.arrows = make([]Arrow, len(c.data))
+	for w := Word(c); w != nil; w = w.Next() {
+		if w.direction == horizontal && w.pos%c.columns != 0 {
+			c.arrows[w.pos-1] |= ArrowRight
+		}
+		if w.direction == vertical && w.pos >= c.columns {
+			c.arrows[w.pos-c.columns] |= ArrowDown
+		}
+	}
+}
+
+// IsArrowword reports whether the crossword has clue squares.
+func (c *Crossword) IsArrowword() bool {
+	return c.arrows != nil
+}
+
+// Arrow returns the words the square points to if it's a clue square of an
+// arrowword, 0 otherwise.
+func (l *LetterRef) Arrow() Arrow {
+	if l.crossword.arrows == nil {
+		return 0
+	}
+	return l.crossword.arrows[l.pos]
+}
+
+// IsClue reports whether the square is a clue square of an arrowword.
+func (l *LetterRef) IsClue() bool {
+	return l.Arrow() != 0
+}
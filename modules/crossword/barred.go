@@ -0,0 +1,99 @@
+package crossword
+
+import "math/rand"
+
+// Bar tells which edges of a square of a barred crossword are bars. A bar
+// separates two letters of the same row or column that belong to different
+// words.
+type Bar byte
+
+const (
+	BarRight Bar = 1 << iota
+	BarBottom
+)
+
+// barredMinRun and barredMaxRun bound the length of the words of the layouts
+// of BarredShaper.
+const (
+	barredMinRun = 3
+	barredMaxRun = 5
+)
+
+// BarShaper is a Shaper which also separates words with bars. Bars returns
+// the bars of rows*columns squares, in row-major order, for the mask returned
+// by Shape.
+type BarShaper interface {
+	Shaper
+	Bars(mask []bool, rows, columns int, random *rand.Rand) []Bar
+}
+
+// BarredShaper lays out barred crosswords, which have no blank squares. Every
+// row is split by bars into across words, and every even column into down
+// words; the letters of odd columns are only checked by their across word.
+type BarredShaper struct{}
+
+func (BarredShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	return make([]bool, rows*columns)
+}
+
+func (BarredShaper) Bars(mask []bool, rows, columns int, random *rand.Rand) []Bar {
+	bars := make([]Bar, rows*columns)
+	for row := range rows {
+		for _, end := range splitRun(columns, random) {
+			bars[row*columns+end] |= BarRight
+		}
+	}
+	for column := range columns {
+		if column%2 == 0 {
+			for _, end := range splitRun(rows, random) {
+				bars[end*columns+column] |= BarBottom
+			}
+			continue
+		}
+		for row := range rows - 1 {
+			bars[row*columns+column] |= BarBottom
+		}
+	}
+	return bars
+}
+
+// splitRun splits a run of squares into words of barredMinRun to barredMaxRun
+// letters and returns the index of the last letter of every word but the last
+// one. Runs too short to be split are left whole.
+func splitRun(length int, random *rand.Rand) []int {
+	ends := []int{}
+	start := 0
+	for length-start > barredMaxRun {
+		longest := min(barredMaxRun, length-start-barredMinRun)
+		start += barredMinRun + random.Intn(longest-barredMinRun+1)
+		ends = append(ends, start-1)
+	}
+	return ends
+}
+
+// AddBar adds bars to the edges of a square, for barred crosswords laid out by
+// other means than NewCrossword.
+func (c *Crossword) AddBar(row, column int, bar Bar) {
+	if c.bars == nil {
+		c.bars = make([]Bar, len(c.data))
+	}
+	c.bars[row*c.columns+column] |= bar
+}
+
+// IsBarred reports whether the words of the crossword are separated by bars.
+func (c *Crossword) IsBarred() bool {
+	return c.bars != nil
+}
+
+// hasBar reports whether the square at pos has the given bar.
+func (c *Crossword) hasBar(pos int, bar Bar) bool {
+	return c.bars != nil && c.bars[pos]&bar != 0
+}
+
+// Bars returns the bars of the square, 0 if it has none.
+func (l *LetterRef) Bars() Bar {
+	if l.crossword.bars == nil {
+		return 0
+	}
+	return l.crossword.bars[l.pos]
+}
@@ -0,0 +1,55 @@
+package crossword
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// commonWordsTimeout is the time spent filling a grid with the common words
+// of a frequency tier before falling back to more words, when the generation
+// has no deadline.
+const commonWordsTimeout = 10 * time.Second
+
+// ErrNoFrequencies is returned by NewCrossword when common words are required
+// from a dictionary that doesn't rank its words by frequency.
+var ErrNoFrequencies = errors.New("the dictionary has no word frequencies")
+
+// commonWordLimits returns the numbers of most common words a grid is filled
+// with in turn: CommonWords, then the larger frequency tiers and finally all
+// the words, 0.
+func (config CrosswordConfig) commonWordLimits() ([]int, error) {
+	if config.CommonWords <= 0 {
+		return []int{0}, nil
+	}
+	frequencies, ok := config.WordDict.(dictionary.FrequencySource)
+	if !ok || frequencies.Ranked() == 0 {
+		return nil, ErrNoFrequencies
+	}
+	limits := []int{config.CommonWords}
+	for _, limit := range dictionary.FrequencyTiers {
+		if limit > config.CommonWords {
+			limits = append(limits, limit)
+		}
+	}
+	return append(limits, 0), nil
+}
+
+// tierTimeout returns the time spent on every limit but the last one, out of
+// the given number of limits: an equal share of the time left before the
+// deadline of ctx, such as the one of Timeout or of a density range, and
+// commonWordsTimeout without deadline.
+func tierTimeout(ctx context.Context, limits int) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline) / time.Duration(limits)
+	}
+	return commonWordsTimeout
+}
+
+// fallsBack reports whether a grid that couldn't be filled with a number of
+// common words because of the error should be filled with more words.
+func fallsBack(err error) bool {
+	return errors.Is(err, ErrUnfillable) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrRequiredWords)
+}
@@ -0,0 +1,157 @@
+package crossword
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+)
+
+// Clone returns a copy of the crossword which can be filled or changed
+// without affecting the original.
+func (c *Crossword) Clone() *Crossword {
+	return &Crossword{
+		rows:    c.rows,
+		columns: c.columns,
+		data:    slices.Clone(c.data),
+		arrows:  slices.Clone(c.arrows),
+		bars:    slices.Clone(c.bars),
+		masked:  slices.Clone(c.masked),
+	}
+}
+
+// Equal reports whether the crosswords have the same size, squares, clue
+// squares, bars and masked squares.
+func (c *Crossword) Equal(other *Crossword) bool {
+	if c.rows != other.rows || c.columns != other.columns || !slices.Equal(c.data, other.data) {
+		return false
+	}
+	for pos := range c.data {
+		if c.isMasked(pos) != other.isMasked(pos) ||
+			(&LetterRef{pos: pos, crossword: c}).Arrow() != (&LetterRef{pos: pos, crossword: other}).Arrow() ||
+			(&LetterRef{pos: pos, crossword: c}).Bars() != (&LetterRef{pos: pos, crossword: other}).Bars() {
+			return false
+		}
+	}
+	return true
+}
+
+// Fingerprint returns a hash of the content of the crossword, the same for
+// equal crosswords, to deduplicate them.
+func (c *Crossword) Fingerprint() string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%dx%d\n%s", c.rows, c.columns, c.String())
+	// arrows and bars are only hashed when present, as Equal doesn't tell
+	// missing ones from zero ones
+	for pos := range c.data {
+		letter := LetterRef{pos: pos, crossword: c}
+		if arrow, bars := letter.Arrow(), letter.Bars(); arrow != 0 || bars != 0 {
+			fmt.Fprintf(hash, "%d:%d:%d\n", pos, arrow, bars)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// CellChange is a square whose value differs between two crosswords, Before
+// and After being the values returned by LetterRef.GetValue.
+type CellChange struct {
+	Row    int
+	Column int
+	Before byte
+	After  byte
+}
+
+// EntryChange is a word whose letters differ between two crosswords, written
+// with Empty for empty squares. Before or After is empty if the word is only
+// in one of the crosswords.
+type EntryChange struct {
+	Row    int
+	Column int
+	Across bool
+	Before string
+	After  string
+}
+
+// Difference lists the changes between two crosswords, in the order of
+// CrosswordLetter and Word.
+type Difference struct {
+	Cells   []CellChange
+	Entries []EntryChange
+}
+
+// IsEmpty reports whether the crosswords have the same squares.
+func (d Difference) IsEmpty() bool {
+	return len(d.Cells) == 0 && len(d.Entries) == 0
+}
+
+// Diff returns the squares and the words that changed from a to b, such as
+// the mistakes of a solver's grid b against the solution a. The crosswords
+// must have the same size.
+func Diff(a, b *Crossword) (Difference, error) {
+	if a.rows != b.rows || a.columns != b.columns {
+		return Difference{}, fmt.Errorf("can't compare a %dx%d crossword with a %dx%d one", a.rows, a.columns, b.rows, b.columns)
+	}
+
+	d := Difference{Cells: []CellChange{}, Entries: []EntryChange{}}
+	for pos := range a.data {
+		if a.data[pos] != b.data[pos] {
+			d.Cells = append(d.Cells, CellChange{
+				Row:    pos / a.columns,
+				Column: pos % a.columns,
+				Before: a.data[pos],
+				After:  b.data[pos],
+			})
+		}
+	}
+
+	before, after := entryValues(a), entryValues(b)
+	keys := []entryKey{}
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, found := before[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	// across words first, like Word
+	slices.SortFunc(keys, func(x, y entryKey) int {
+		return cmp.Or(cmp.Compare(x.direction, y.direction), cmp.Compare(x.pos, y.pos))
+	})
+	for _, key := range keys {
+		if before[key] != after[key] {
+			d.Entries = append(d.Entries, EntryChange{
+				Row:    key.pos / a.columns,
+				Column: key.pos % a.columns,
+				Across: key.direction == horizontal,
+				Before: before[key],
+				After:  after[key],
+			})
+		}
+	}
+	return d, nil
+}
+
+// entryKey identifies a word by its start and direction, to match the words
+// of two crosswords.
+type entryKey struct {
+	pos       int
+	direction wordDirection
+}
+
+// entryValues returns the words of the crossword, written with Empty for
+// empty squares.
+func entryValues(c *Crossword) map[entryKey]string {
+	values := map[entryKey]string{}
+	for w := Word(c); w != nil; w = w.Next() {
+		value := w.GetValue()
+		for i := range value {
+			if value[i] == 0 {
+				value[i] = Empty
+			}
+		}
+		values[entryKey{w.pos, w.direction}] = string(value)
+	}
+	return values
+}
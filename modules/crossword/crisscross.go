@@ -0,0 +1,294 @@
+package crossword
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// crissCrossAttempts is the number of word orders tried by newCrissCross, the
+// most interlocked and compact layout being kept.
+const crissCrossAttempts = 100
+
+// ErrNoInterlock is returned by NewCrossword when the words of a criss-cross
+// can't all be placed in a single interlocking layout, because some of them
+// share no letter with the others or the layout doesn't fit the requested
+// size.
+var ErrNoInterlock = errors.New("the words can't be placed in an interlocking layout")
+
+type gridPoint struct {
+	row    int
+	column int
+}
+
+func (p gridPoint) step(direction wordDirection, n int) gridPoint {
+	if direction == horizontal {
+		return gridPoint{row: p.row, column: p.column + n}
+	}
+	return gridPoint{row: p.row + n, column: p.column}
+}
+
+// crissCrossCell is a square of a criss-cross holding a letter, along with the
+// directions of the words it belongs to.
+type crissCrossCell struct {
+	letter byte
+	across bool
+	down   bool
+}
+
+func (c *crissCrossCell) in(direction wordDirection) bool {
+	if direction == horizontal {
+		return c.across
+	}
+	return c.down
+}
+
+type crissCrossPlacement struct {
+	word      string
+	start     gridPoint
+	direction wordDirection
+}
+
+// crissCross is a freestyle layout growing word by word on an unbounded grid,
+// every new word crossing the words already placed at shared letters.
+type crissCross struct {
+	cells     map[gridPoint]*crissCrossCell
+	points    []gridPoint
+	placed    int
+	crossings int
+	low, high gridPoint
+}
+
+func newCrissCross(config CrosswordConfig) (CrosswordResult, error) {
+	if len(config.Words) < 2 {
+		return CrosswordResult{}, fmt.Errorf("a criss-cross needs at least 2 words, got %d", len(config.Words))
+	}
+	words, err := normalizeWords(config.Words)
+	if err != nil {
+		return CrosswordResult{}, err
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = newSeed(rand.New(rand.NewSource(rand.Int63())))
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	var best *crissCross
+	for attempt := range crissCrossAttempts {
+		order := slices.Clone(words)
+		random.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		// placing the longest words first usually gives the most compact
+		// layouts, the other attempts explore different orders
+		if attempt%2 == 0 {
+			slices.SortStableFunc(order, func(a, b string) int {
+				return len(b) - len(a)
+			})
+		}
+
+		layout := layCrissCross(order, config.Rows, config.Cols, random)
+		if layout.placed == len(words) && layout.betterThan(best) {
+			best = layout
+		}
+	}
+	if best == nil {
+		return CrosswordResult{}, ErrNoInterlock
+	}
+	return newCrosswordResult(best.crossword(), seed), nil
+}
+
+// normalizeWords lowercases the given words and checks that they are distinct
+// words of at least two letters.
+func normalizeWords(words []string) ([]string, error) {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len(word) < 2 {
+			return nil, fmt.Errorf("word %q is shorter than 2 letters", word)
+		}
+		for i := range len(word) {
+			if word[i] < 'a' || word[i] > 'z' {
+				return nil, fmt.Errorf("word %q contains %q, only letters are allowed", word, word[i])
+			}
+		}
+		if slices.Contains(normalized, word) {
+			return nil, fmt.Errorf("word %q is repeated", word)
+		}
+		normalized = append(normalized, word)
+	}
+	return normalized, nil
+}
+
+// layCrissCross places the words in the given order, each one where it
+// crosses the most words while keeping the layout within rows x columns (0
+// meaning no bound) and as small as possible. Words that can't be placed yet
+// are retried once others have been placed.
+func layCrissCross(words []string, rows, columns int, random *rand.Rand) *crissCross {
+	layout := &crissCross{cells: map[gridPoint]*crissCrossCell{}}
+	switch {
+	case columns == 0 || len(words[0]) <= columns:
+		layout.place(crissCrossPlacement{word: words[0], direction: horizontal})
+	case rows == 0 || len(words[0]) <= rows:
+		layout.place(crissCrossPlacement{word: words[0], direction: vertical})
+	default:
+		return layout
+	}
+
+	pending := words[1:]
+	for len(pending) > 0 {
+		remaining := []string{}
+		for _, word := range pending {
+			placement, ok := layout.bestPlacement(word, rows, columns, random)
+			if !ok {
+				remaining = append(remaining, word)
+				continue
+			}
+			layout.place(placement)
+		}
+		if len(remaining) == len(pending) {
+			break
+		}
+		pending = remaining
+	}
+	return layout
+}
+
+// bestPlacement returns the placement of the word crossing the most words,
+// ties being broken by the smallest resulting area and then at random.
+func (l *crissCross) bestPlacement(word string, rows, columns int, random *rand.Rand) (crissCrossPlacement, bool) {
+	placements := []crissCrossPlacement{}
+	for _, point := range l.points {
+		cell := l.cells[point]
+		for i := range len(word) {
+			if word[i] != cell.letter {
+				continue
+			}
+			for _, direction := range []wordDirection{horizontal, vertical} {
+				if !cell.in(direction) {
+					placements = append(placements, crissCrossPlacement{word: word, start: point.step(direction, -i), direction: direction})
+				}
+			}
+		}
+	}
+	random.Shuffle(len(placements), func(i, j int) {
+		placements[i], placements[j] = placements[j], placements[i]
+	})
+
+	best, bestCrossings, bestArea := crissCrossPlacement{}, 0, 0
+	for _, placement := range placements {
+		crossings, ok := l.fits(placement)
+		if !ok {
+			continue
+		}
+		low, high := l.bounds(placement)
+		height, width := high.row-low.row+1, high.column-low.column+1
+		if (rows > 0 && height > rows) || (columns > 0 && width > columns) {
+			continue
+		}
+		area := height * width
+		if crossings > bestCrossings || (crossings == bestCrossings && area < bestArea) {
+			best, bestCrossings, bestArea = placement, crossings, area
+		}
+	}
+	return best, bestCrossings > 0
+}
+
+// fits reports whether the placement only crosses other words at matching
+// letters without touching them anywhere else, and returns the number of
+// words it crosses.
+func (l *crissCross) fits(placement crissCrossPlacement) (int, bool) {
+	if l.cells[placement.start.step(placement.direction, -1)] != nil ||
+		l.cells[placement.start.step(placement.direction, len(placement.word))] != nil {
+		return 0, false
+	}
+
+	crossing := vertical
+	if placement.direction == vertical {
+		crossing = horizontal
+	}
+	crossings := 0
+	for i := range len(placement.word) {
+		point := placement.start.step(placement.direction, i)
+		if cell := l.cells[point]; cell != nil {
+			if cell.letter != placement.word[i] || cell.in(placement.direction) {
+				return 0, false
+			}
+			crossings++
+			continue
+		}
+		// a new letter can't sit next to a letter of a parallel word
+		if l.cells[point.step(crossing, -1)] != nil || l.cells[point.step(crossing, 1)] != nil {
+			return 0, false
+		}
+	}
+	return crossings, true
+}
+
+// bounds returns the corners of the layout once the placement is made.
+func (l *crissCross) bounds(placement crissCrossPlacement) (gridPoint, gridPoint) {
+	end := placement.start.step(placement.direction, len(placement.word)-1)
+	return gridPoint{row: min(l.low.row, placement.start.row), column: min(l.low.column, placement.start.column)},
+		gridPoint{row: max(l.high.row, end.row), column: max(l.high.column, end.column)}
+}
+
+func (l *crissCross) place(placement crissCrossPlacement) {
+	if l.placed == 0 {
+		l.low, l.high = placement.start, placement.start
+	}
+	l.low, l.high = l.bounds(placement)
+	for i := range len(placement.word) {
+		point := placement.start.step(placement.direction, i)
+		cell := l.cells[point]
+		if cell == nil {
+			cell = &crissCrossCell{letter: placement.word[i]}
+			l.cells[point] = cell
+			l.points = append(l.points, point)
+		} else {
+			l.crossings++
+		}
+		if placement.direction == horizontal {
+			cell.across = true
+		} else {
+			cell.down = true
+		}
+	}
+	l.placed++
+}
+
+func (l *crissCross) area() int {
+	return (l.high.row - l.low.row + 1) * (l.high.column - l.low.column + 1)
+}
+
+// betterThan reports whether the layout has more crossings than the other
+// one, or as many crossings in a smaller area.
+func (l *crissCross) betterThan(other *crissCross) bool {
+	if other == nil {
+		return true
+	}
+	if l.crossings != other.crossings {
+		return l.crossings > other.crossings
+	}
+	return l.area() < other.area()
+}
+
+// crossword converts the layout into a crossword, the squares without letters
+// being blank.
+func (l *crissCross) crossword() *Crossword {
+	rows, columns := l.high.row-l.low.row+1, l.high.column-l.low.column+1
+	data := make([]byte, rows*columns)
+	for i := range data {
+		data[i] = Blank
+	}
+	for point, cell := range l.cells {
+		data[(point.row-l.low.row)*columns+point.column-l.low.column] = cell.letter
+	}
+	return &Crossword{
+		rows:    rows,
+		columns: columns,
+		data:    data,
+	}
+}
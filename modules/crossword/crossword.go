@@ -2,8 +2,11 @@ package crossword
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/ahboujelben/go-crossword/modules/dictionary"
 )
@@ -14,19 +17,170 @@ type Crossword struct {
 	rows    int
 	columns int
 	data    []byte
+	// arrows are the clue squares of an arrowword, nil for other crosswords.
+	arrows []Arrow
+	// bars separate the words of a barred crossword, nil for other
+	// crosswords.
+	bars []Bar
+	// masked marks the squares outside the playing area of a crossword with
+	// an Outline, nil for rectangular crosswords.
+	masked []bool
 }
 
 type CrosswordConfig struct {
 	Rows     int
 	Cols     int
 	Threads  int
-	WordDict dictionary.WordDictionary
+	WordDict dictionary.WordSource
 	Seed     int64
+	// Shaper lays out the blank squares of the grid. ClassicShaper is used
+	// when nil.
+	Shaper Shaper
+	// LimitUnchecked and MaxUncheckedRatio bound the proportion of letters
+	// belonging to a single word (see LayoutRules).
+	LimitUnchecked    bool
+	MaxUncheckedRatio float64
+	// MinWordLength and MaxWordLength bound the length of the words, zero
+	// meaning no bound (see LayoutRules). Layouts are shaped to follow them.
+	MinWordLength int
+	MaxWordLength int
+	// MinDensity and MaxDensity bound the proportion of blank squares of the
+	// grid, zero meaning no bound (see LayoutRules). Blank squares are added to
+	// or removed from the layouts to follow them. As grids with few blank
+	// squares may not be fillable, the range is raised by 5% at a time, up to
+	// 40%, when the grid can't be filled within densityStepTimeout (see
+	// CrosswordResult.MinDensity), and the generation is bounded by
+	// densityTimeout unless Timeout is set.
+	MinDensity float64
+	MaxDensity float64
+	// Outline shapes non-rectangular grids, whose squares outside the playing
+	// area are masked. The grid is rectangular when nil, and criss-crosses
+	// ignore it.
+	Outline Outline
+	// Timeout bounds the time spent generating the crossword. Zero means no
+	// limit.
+	Timeout time.Duration
+	// Mini generates a dense mini puzzle, such as a double word square, laid
+	// out by a MiniShaper with MiniBlocks blank squares unless Shaper is set.
+	Mini       bool
+	MiniBlocks int
+	// Words builds a freestyle criss-cross out of the given words only,
+	// instead of filling a grid with words from WordDict. Rows and Cols then
+	// bound the size of the layout, zero meaning no bound.
+	Words []string
+	// Arrowword lays out an arrowword, whose clues are written in blank
+	// squares next to their answers, around the layout of Shaper.
+	Arrowword bool
+	// Exclude lists words the crossword must not contain, such as recently
+	// used answers.
+	Exclude []string
+	// Require lists words the crossword must contain, wherever they fit. They
+	// don't need to be in WordDict.
+	Require []string
+	// Penalize lists words the generator picks less often without excluding
+	// them, such as answers used a while ago.
+	Penalize []string
+	// ExcludeLayouts lists layouts (see Crossword.Layout) the crossword must
+	// not have, and PenalizeLayouts layouts it only has when the shaper yields
+	// no other valid one.
+	ExcludeLayouts  []string
+	PenalizeLayouts []string
+	// MinDifficulty and MaxDifficulty bound the difficulty score of the
+	// crossword (see RateDifficulty), zero meaning no bound. Crosswords out of
+	// range are regenerated, unless Seed is set.
+	MinDifficulty float64
+	MaxDifficulty float64
+	// CommonWords restricts the words of the crossword to the CommonWords
+	// most common words of WordDict, which must be a
+	// dictionary.FrequencySource, zero meaning all words. When the grid can't
+	// be filled in time, the larger frequency tiers and then all the words
+	// are tried in turn, sharing the time of each density range (see
+	// CrosswordResult.CommonWords).
+	CommonWords int
+	// Accept rejects the crosswords for which it returns false, such as the
+	// ones failing an editorial review. Rejected crosswords are regenerated,
+	// unless Seed is set.
+	Accept func(c *Crossword) bool
+}
+
+// ErrNoLayout is returned by NewCrossword when the shaper can't produce a
+// layout satisfying the layout rules of the configuration.
+var ErrNoLayout = errors.New("no layout satisfies the layout rules")
+
+// ErrTimeout is returned by NewCrossword when no crossword could be generated
+// within the configured timeout.
+var ErrTimeout = errors.New("crossword generation timed out")
+
+// ErrRequiredWords is returned by NewCrossword when the required words can't
+// all be placed in the layouts of the configuration.
+var ErrRequiredWords = errors.New("the required words can't be placed in the crossword")
+
+// ErrDifficulty is returned by NewCrossword when no crossword within the
+// configured difficulty range was generated.
+var ErrDifficulty = errors.New("no crossword within the difficulty range was generated")
+
+// ErrRejected is returned by NewCrossword when every crossword generated was
+// rejected by the Accept function of the configuration.
+var ErrRejected = errors.New("every crossword generated was rejected")
+
+// ErrUnfillable is returned by NewCrossword when the layout produced by the
+// configured seed can't be filled with words from the dictionary.
+var ErrUnfillable = errors.New("the crossword layout can't be filled")
+
+func (config CrosswordConfig) shaper() Shaper {
+	switch {
+	case config.Arrowword:
+		return ArrowwordShaper{Inner: config.Shaper}
+	case config.Shaper != nil:
+		return config.Shaper
+	case config.Mini:
+		return MiniShaper{Blocks: config.MiniBlocks}
+	}
+	return ClassicShaper{}
+}
+
+func (config CrosswordConfig) layoutRules() LayoutRules {
+	return LayoutRules{
+		LimitUnchecked:    config.LimitUnchecked,
+		MaxUncheckedRatio: config.MaxUncheckedRatio,
+		MinWordLength:     config.MinWordLength,
+		MaxWordLength:     config.MaxWordLength,
+		MinDensity:        config.MinDensity,
+		MaxDensity:        config.MaxDensity,
+	}
 }
 
 type CrosswordResult struct {
-	Crossword *Crossword
-	Seed      int64
+	Crossword  *Crossword
+	Seed       int64
+	Difficulty Difficulty
+	// CommonWords is the number of most common words the crossword was
+	// filled with, 0 for all the words. It is larger than the configured
+	// CommonWords when the grid couldn't be filled with fewer words, and
+	// reproduces the crossword along with Seed.
+	CommonWords int
+	// MinDensity and MaxDensity are the density range the crossword was
+	// generated within. It is higher than the configured one when the grid
+	// couldn't be filled with fewer blank squares, and reproduces the
+	// crossword along with Seed.
+	MinDensity float64
+	MaxDensity float64
+}
+
+// maxAcceptanceAttempts is the number of crosswords generated by NewCrossword
+// before giving up on one within the configured difficulty range and accepted
+// by the configuration.
+const maxAcceptanceAttempts = 20
+
+// acceptsDifficulty reports whether the difficulty is within the configured
+// range.
+func (config CrosswordConfig) acceptsDifficulty(d Difficulty) bool {
+	return d.Score >= config.MinDifficulty && (config.MaxDifficulty == 0 || d.Score <= config.MaxDifficulty)
+}
+
+// accepts reports whether the crossword is accepted by the configuration.
+func (config CrosswordConfig) accepts(c *Crossword) bool {
+	return config.Accept == nil || config.Accept(c)
 }
 
 func newCrosswordResult(crossword *Crossword, seed int64) CrosswordResult {
@@ -36,36 +190,213 @@ func newCrosswordResult(crossword *Crossword, seed int64) CrosswordResult {
 	}
 }
 
-func NewCrossword(config CrosswordConfig) CrosswordResult {
-	ctx, cancel := context.WithCancel(context.Background())
+func NewCrossword(config CrosswordConfig) (CrosswordResult, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	switch {
+	case config.Timeout > 0:
+		ctx, cancel = context.WithTimeout(context.Background(), config.Timeout)
+	case config.layoutRules().boundsDensity():
+		ctx, cancel = context.WithTimeout(context.Background(), densityTimeout)
+	default:
+		ctx, cancel = context.WithCancel(context.Background())
+	}
 	defer cancel()
 
-	solvedCrossword := make(chan CrosswordResult, 1)
+	if len(config.Words) > 0 {
+		result, err := newCrissCross(config)
+		if err == nil {
+			result.Difficulty = RateDifficulty(result.Crossword, config.WordDict)
+		}
+		return result, err
+	}
+	if _, barred := config.Shaper.(BarShaper); barred && config.Arrowword {
+		return CrosswordResult{}, errors.New("an arrowword can't be barred")
+	}
+	if config.Outline != nil && config.Arrowword {
+		return CrosswordResult{}, errors.New("an arrowword can't have an outline")
+	}
+	if config.MinDifficulty < 0 || config.MinDifficulty > 1 || config.MaxDifficulty < 0 || config.MaxDifficulty > 1 ||
+		(config.MaxDifficulty > 0 && config.MinDifficulty > config.MaxDifficulty) {
+		return CrosswordResult{}, fmt.Errorf("invalid difficulty range [%.2f, %.2f]", config.MinDifficulty, config.MaxDifficulty)
+	}
+	if config.LimitUnchecked && (config.MaxUncheckedRatio < 0 || config.MaxUncheckedRatio > 1) {
+		return CrosswordResult{}, fmt.Errorf("invalid unchecked ratio %.2f", config.MaxUncheckedRatio)
+	}
+	if config.MinWordLength < 0 || config.MaxWordLength < 0 || (config.MaxWordLength > 0 && config.MaxWordLength < max(config.MinWordLength, 2)) {
+		return CrosswordResult{}, fmt.Errorf("invalid word length range [%d, %d]", config.MinWordLength, config.MaxWordLength)
+	}
+	if config.MinDensity < 0 || config.MaxDensity < 0 || config.MaxDensity > 1 ||
+		(config.MaxDensity > 0 && config.MinDensity > config.MaxDensity) {
+		return CrosswordResult{}, fmt.Errorf("invalid density range [%.2f, %.2f]", config.MinDensity, config.MaxDensity)
+	}
+	if _, barred := config.Shaper.(BarShaper); barred && config.MinDensity > 0 {
+		return CrosswordResult{}, errors.New("a barred crossword has no blank squares")
+	}
+	limits, err := config.commonWordLimits()
+	if err != nil {
+		return CrosswordResult{}, err
+	}
+
+	// grids with few blank squares may not be fillable, so the density range
+	// is raised when the grid can't be filled in time.
+	ranges := config.densityRanges()
+	for i, bounds := range ranges {
+		rangeConfig, rangeCtx, rangeCancel := config, ctx, context.CancelFunc(func() {})
+		rangeConfig.MinDensity, rangeConfig.MaxDensity = bounds.min, bounds.max
+		if i < len(ranges)-1 {
+			rangeCtx, rangeCancel = context.WithTimeout(ctx, config.densityStepTimeout(len(ranges)))
+		}
+		result, err := generateCommon(rangeCtx, rangeConfig, limits)
+		rangeCancel()
+		if err == nil {
+			result.MinDensity, result.MaxDensity = bounds.min, bounds.max
+			return result, nil
+		}
+		if i == len(ranges)-1 || ctx.Err() != nil || !fallsBackDensity(err) {
+			return CrosswordResult{}, err
+		}
+	}
+	return CrosswordResult{}, ErrUnfillable
+}
+
+// generateCommon generates a crossword of the configuration with the most
+// common words first, falling back to more words when the grid can't be
+// filled in time.
+func generateCommon(ctx context.Context, config CrosswordConfig, limits []int) (CrosswordResult, error) {
+	// the tiers share the time of the density range, if any
+	timeout := tierTimeout(ctx, len(limits))
+	for i, limit := range limits {
+		tierConfig, tierCtx, tierCancel := config, ctx, context.CancelFunc(func() {})
+		if limit > 0 {
+			tierConfig.WordDict = config.WordDict.(dictionary.FrequencySource).Common(limit)
+		}
+		if i < len(limits)-1 {
+			tierCtx, tierCancel = context.WithTimeout(ctx, timeout)
+		}
+		result, err := generateRated(tierCtx, tierConfig, config.WordDict)
+		tierCancel()
+		if err == nil {
+			result.CommonWords = limit
+			return result, nil
+		}
+		if i == len(limits)-1 || ctx.Err() != nil || !fallsBack(err) {
+			return CrosswordResult{}, err
+		}
+	}
+	return CrosswordResult{}, ErrUnfillable
+}
+
+// generateRated generates crosswords of the configuration until one is within
+// its difficulty range and accepted, rating the rarity of their answers with wordDict.
+func generateRated(ctx context.Context, config CrosswordConfig, wordDict dictionary.WordSource) (CrosswordResult, error) {
+	config, err := config.withWordSets()
+	if err != nil {
+		return CrosswordResult{}, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := fillCrossword(ctx, config)
+		if err != nil {
+			return CrosswordResult{}, err
+		}
+		result.Difficulty = RateDifficulty(result.Crossword, wordDict)
+		inRange := config.acceptsDifficulty(result.Difficulty)
+		if inRange && config.accepts(result.Crossword) {
+			return result, nil
+		}
+		if config.Seed != 0 || attempt == maxAcceptanceAttempts {
+			if !inRange {
+				return CrosswordResult{}, ErrDifficulty
+			}
+			return CrosswordResult{}, ErrRejected
+		}
+	}
+}
+
+// fillCrossword generates a crossword of the configuration, whose word sets
+// have been applied.
+func fillCrossword(ctx context.Context, config CrosswordConfig) (CrosswordResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	if config.Seed != 0 {
-		generateCrossword(ctx, config.Rows, config.Cols, config.Seed, config.WordDict, solvedCrossword)
-		return <-solvedCrossword
+		// a seed that won a race below was filled within its restart cutoff, so
+		// replaying it without a cutoff follows exactly the same path.
+		crossword, err := generateCrossword(ctx, config, config.Seed, 0)
+		switch {
+		case err != nil:
+			return CrosswordResult{}, err
+		case crossword == nil && ctx.Err() != nil:
+			return CrosswordResult{}, ErrTimeout
+		case crossword == nil:
+			return CrosswordResult{}, ErrUnfillable
+		}
+		return newCrosswordResult(crossword, config.Seed), nil
 	}
 
+	solvedCrossword := make(chan CrosswordResult, 1)
+	failure := make(chan error, 1)
+
 	var wg sync.WaitGroup
 
 	// Generating a random crossword can take an unpredictable amount of time,
 	// depending on the initial crossword configuration and the words that are
 	// tried. To speed up the process, we run multiple goroutines to generate
-	// crosswords and return the first one that is solved. This typically takes
-	// less than a second to generate a 13x13 crossword.
+	// crosswords and return the first one that is solved. Each goroutine
+	// abandons layouts that are hard to fill and restarts with a fresh one (see
+	// restartPolicy), so a handful of goroutines is usually enough.
 	for range config.Threads {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			defer cancel()
-			generateCrossword(ctx, config.Rows, config.Cols, rand.Int63(), config.WordDict, solvedCrossword)
+			seeds := rand.New(rand.NewSource(rand.Int63()))
+			policy := newRestartPolicy()
+			for ctx.Err() == nil {
+				seed := newSeed(seeds)
+				crossword, err := generateCrossword(ctx, config, seed, policy.next())
+				if err != nil {
+					select {
+					case failure <- err:
+					default:
+					}
+					return
+				}
+				if crossword != nil {
+					select {
+					case solvedCrossword <- newCrosswordResult(crossword, seed):
+					default:
+					}
+					return
+				}
+			}
 		}()
 	}
 
 	wg.Wait()
 
-	return <-solvedCrossword
+	select {
+	case result := <-solvedCrossword:
+		return result, nil
+	default:
+	}
+	select {
+	case err := <-failure:
+		return CrosswordResult{}, err
+	default:
+		return CrosswordResult{}, ErrTimeout
+	}
+}
+
+// NewGrid returns a crossword of the given size whose squares are all empty,
+// for puzzles laid out by other means than NewCrossword.
+func NewGrid(rows, columns int) *Crossword {
+	return &Crossword{
+		rows:    rows,
+		columns: columns,
+		data:    make([]byte, rows*columns),
+	}
 }
 
 func (c *Crossword) Columns() int {
@@ -1,8 +1,15 @@
 package crossword_test
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ahboujelben/go-crossword/modules/crossword"
 	"github.com/ahboujelben/go-crossword/modules/dictionary"
@@ -17,13 +24,14 @@ func TestGenerateCrossword(t *testing.T) {
 			r := rows
 			t.Run(fmt.Sprintf("Rows=%d_Columns=%d", rows, columns), func(t *testing.T) {
 				t.Parallel()
-				result := crossword.NewCrossword(crossword.CrosswordConfig{
+				result, err := crossword.NewCrossword(crossword.CrosswordConfig{
 					Rows:     r,
 					Cols:     c,
 					Threads:  100,
 					WordDict: wordDict,
 				})
 
+				assert.NoError(t, err)
 				assert.True(t, result.Crossword.IsFilled())
 				for word := crossword.ColumnWord(result.Crossword); word != nil; word = word.Next() {
 					wordValue := string(word.GetValue())
@@ -33,3 +41,1017 @@ func TestGenerateCrossword(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateCrosswordFromWordSource(t *testing.T) {
+	wordDict := dictionary.Weighted(
+		dictionary.Filtered(dictionary.NewWordDictionary(), func(word string) bool {
+			return !strings.Contains(word, "s")
+		}),
+		func(word string) float64 {
+			return float64(len(word))
+		},
+	)
+	for _, mini := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Mini=%t", mini), func(t *testing.T) {
+			result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+				Rows:     4,
+				Cols:     4,
+				Threads:  4,
+				WordDict: wordDict,
+				Mini:     mini,
+			})
+
+			assert.NoError(t, err)
+			for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+				assert.True(t, wordDict.Contains(string(word.GetValue())))
+				assert.NotContains(t, string(word.GetValue()), "s")
+			}
+		})
+	}
+}
+
+func TestGenerateCrosswordWithWordSets(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	exclude := []string{}
+	for _, index := range wordDict.Candidates([]byte{'a', 0, 0}) {
+		exclude = append(exclude, wordDict.Word(index))
+	}
+	require := []string{"Gopher", "zebra", "qwx"}
+
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     9,
+		Cols:     9,
+		Threads:  4,
+		WordDict: wordDict,
+		Exclude:  exclude,
+		Require:  require,
+	})
+
+	assert.NoError(t, err)
+	words := []string{}
+	for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+		words = append(words, string(word.GetValue()))
+	}
+	for _, word := range []string{"gopher", "zebra", "qwx"} {
+		assert.Contains(t, words, word)
+	}
+	for _, word := range exclude {
+		assert.NotContains(t, words, word)
+	}
+
+	for _, config := range []crossword.CrosswordConfig{
+		{Rows: 5, Cols: 5, Require: []string{"gopher"}},
+		{Rows: 5, Cols: 5, Require: []string{"cat"}, Exclude: []string{"CAT"}},
+		{Rows: 5, Cols: 5, Require: []string{"c4t"}},
+	} {
+		config.Threads, config.WordDict = 1, wordDict
+		_, err := crossword.NewCrossword(config)
+		assert.Error(t, err)
+	}
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     3,
+		Cols:     3,
+		Threads:  1,
+		WordDict: wordDict,
+		Mini:     true,
+		Require:  []string{"cat", "dog", "cow", "pig", "ram", "emu", "owl"},
+	})
+	assert.ErrorIs(t, err, crossword.ErrRequiredWords)
+}
+
+func TestGenerateCrosswordWithRecentLayouts(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	config := crossword.CrosswordConfig{
+		Rows:     3,
+		Cols:     3,
+		Threads:  1,
+		WordDict: wordDict,
+		Mini:     true,
+	}
+	// a mini puzzle without blocks only has one layout
+	layout := "___\n___\n___\n"
+
+	excluded := config
+	excluded.ExcludeLayouts = []string{layout}
+	_, err := crossword.NewCrossword(excluded)
+	assert.ErrorIs(t, err, crossword.ErrNoLayout)
+
+	penalized := config
+	penalized.PenalizeLayouts = []string{layout}
+	result, err := crossword.NewCrossword(penalized)
+	assert.NoError(t, err)
+	assert.Equal(t, layout, result.Crossword.Layout())
+
+	penalized.Rows, penalized.Cols, penalized.Mini = 7, 7, false
+	penalized.Penalize = []string{"cat"}
+	result, err = crossword.NewCrossword(penalized)
+	assert.NoError(t, err)
+	assert.True(t, result.Crossword.IsFilled())
+}
+
+// tieredDictionary ranks the words of a WordDictionary by a map of tiers.
+type tieredDictionary struct {
+	dictionary.WordDictionary
+	tiers map[string]int
+}
+
+func (d tieredDictionary) Tier(word string) (int, bool) {
+	tier, ok := d.tiers[word]
+	return tier, ok
+}
+
+func (d tieredDictionary) Tiers() int {
+	return 3
+}
+
+func TestRateDifficulty(t *testing.T) {
+	grid, err := crossword.ParseCrossword(`
+		cat
+		a.o
+		two
+	`)
+	assert.NoError(t, err)
+	wordDict := dictionary.NewWordDictionaryFromWords([]string{"cat", "two", "too"})
+
+	d := crossword.RateDifficulty(grid, wordDict)
+	assert.False(t, d.Ranked)
+	assert.Equal(t, 0.0, d.Length)
+	assert.Equal(t, 0.5, d.Unchecked)
+	assert.InDelta(t, 8.0/9, d.Openness, 1e-9)
+	assert.InDelta(t, (0.5+8.0/9)/3, d.Score, 1e-9)
+	assert.Equal(t, "medium", d.Label())
+	assert.Equal(t, "medium (0.46, without word rarity)", d.String())
+
+	d = crossword.RateDifficulty(grid, tieredDictionary{wordDict, map[string]int{"cat": 0, "two": 2}})
+	assert.True(t, d.Ranked)
+	assert.Equal(t, 0.5, d.Rarity)
+	assert.InDelta(t, 0.4*0.5+0.2*0.5+0.2*8.0/9, d.Score, 1e-9)
+}
+
+func TestGenerateCrosswordWithinDifficulty(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:          9,
+		Cols:          9,
+		Threads:       4,
+		WordDict:      wordDict,
+		MinDifficulty: 0.5,
+	})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, result.Difficulty.Score, 0.5)
+	assert.Equal(t, crossword.RateDifficulty(result.Crossword, wordDict), result.Difficulty)
+	// the embedded dictionary is ranked, so the rarity of the answers counts
+	assert.True(t, result.Difficulty.Ranked)
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:          5,
+		Cols:          5,
+		Threads:       4,
+		WordDict:      wordDict,
+		MaxDifficulty: 0.01,
+	})
+	assert.ErrorIs(t, err, crossword.ErrDifficulty)
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:          5,
+		Cols:          5,
+		Threads:       4,
+		WordDict:      wordDict,
+		MinDifficulty: 0.8,
+		MaxDifficulty: 0.6,
+	})
+	assert.Error(t, err)
+
+	// scores never exceed 1, so the range is rejected before any generation
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:          5,
+		Cols:          5,
+		Threads:       4,
+		WordDict:      wordDict,
+		MinDifficulty: 1.5,
+	})
+	assert.EqualError(t, err, "invalid difficulty range [1.50, 0.00]")
+}
+
+func TestGenerateCrosswordWithCommonWords(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	_, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:        5,
+		Cols:        5,
+		Threads:     1,
+		WordDict:    dictionary.NewWordDictionaryFromWords(wordDict.AllWords),
+		CommonWords: 3000,
+	})
+	assert.ErrorIs(t, err, crossword.ErrNoFrequencies)
+
+	// the frequencies of the test are made up: the words of the list are
+	// ranked in a random order
+	ranked := slices.Clone(wordDict.AllWords)
+	rand.New(rand.NewSource(1)).Shuffle(len(ranked), func(i, j int) {
+		ranked[i], ranked[j] = ranked[j], ranked[i]
+	})
+	frequent, err := wordDict.WithFrequencies(strings.NewReader(strings.Join(ranked, "\n")))
+	assert.NoError(t, err)
+
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:        7,
+		Cols:        7,
+		Threads:     4,
+		WordDict:    frequent,
+		CommonWords: 10000,
+		Timeout:     20 * time.Second,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Difficulty.Ranked)
+	if result.CommonWords > 0 {
+		for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+			rank, _ := frequent.Rank(string(word.GetValue()))
+			assert.Less(t, rank, result.CommonWords)
+		}
+	}
+
+	// a handful of ranked words can't fill a grid, which falls back to all
+	// the words
+	few, err := wordDict.WithFrequencies(strings.NewReader("cat\ndog\ncow\n"))
+	assert.NoError(t, err)
+	result, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:        5,
+		Cols:        5,
+		Threads:     4,
+		WordDict:    few,
+		CommonWords: 3,
+		Timeout:     2500 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.CommonWords)
+
+	// the tiers share the time of a density range, so all the words are
+	// tried before falling back to a higher density
+	result, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:        9,
+		Cols:        9,
+		Threads:     4,
+		WordDict:    few,
+		CommonWords: 3,
+		MinDensity:  0.2,
+		MaxDensity:  0.25,
+		Timeout:     10 * time.Second,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.CommonWords)
+	assert.Equal(t, 0.2, result.MinDensity)
+	assert.Equal(t, 0.25, result.MaxDensity)
+}
+
+func TestSeedReproducesCrossword(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	config := crossword.CrosswordConfig{
+		Rows:     9,
+		Cols:     9,
+		Threads:  4,
+		WordDict: wordDict,
+	}
+	result, err := crossword.NewCrossword(config)
+	assert.NoError(t, err)
+
+	config.Seed = result.Seed
+	replayed, err := crossword.NewCrossword(config)
+	assert.NoError(t, err)
+
+	assert.Equal(t, result.Seed, replayed.Seed)
+	for letter := crossword.CrosswordLetter(result.Crossword); letter != nil; letter = letter.Next() {
+		replayedLetter := crossword.CrosswordLetterAt(replayed.Crossword, letter.Row(), letter.Column())
+		assert.Equal(t, letter.GetValue(), replayedLetter.GetValue())
+	}
+}
+
+func TestShapers(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	for _, name := range crossword.ShaperNames() {
+		shaper, err := crossword.ShaperByName(name)
+		assert.NoError(t, err)
+		for size := 5; size <= 11; size += 2 {
+			t.Run(fmt.Sprintf("Shape=%s_Size=%d", name, size), func(t *testing.T) {
+				result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+					Rows:     size,
+					Cols:     size,
+					Threads:  4,
+					WordDict: wordDict,
+					Shaper:   shaper,
+				})
+
+				assert.NoError(t, err)
+				assert.True(t, result.Crossword.IsFilled())
+				for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+					assert.True(t, wordDict.Contains(string(word.GetValue())))
+				}
+			})
+		}
+	}
+
+	_, err := crossword.ShaperByName("unknown")
+	assert.Error(t, err)
+}
+
+func TestGenerateMiniCrossword(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	for _, size := range [][3]int{{3, 3, 0}, {4, 4, 0}, {4, 5, 0}, {5, 5, 2}} {
+		rows, columns, blocks := size[0], size[1], size[2]
+		t.Run(fmt.Sprintf("Rows=%d_Columns=%d_Blocks=%d", rows, columns, blocks), func(t *testing.T) {
+			result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+				Rows:       rows,
+				Cols:       columns,
+				Threads:    4,
+				WordDict:   wordDict,
+				Mini:       true,
+				MiniBlocks: blocks,
+			})
+
+			assert.NoError(t, err)
+			assert.True(t, result.Crossword.IsFilled())
+			assert.Equal(t, blocks, strings.Count(result.Crossword.String(), string(crossword.Blank)))
+			words := map[string]bool{}
+			for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+				wordValue := string(word.GetValue())
+				assert.True(t, wordDict.Contains(wordValue))
+				assert.False(t, words[wordValue], "duplicate word %s", wordValue)
+				words[wordValue] = true
+			}
+		})
+	}
+}
+
+func TestGenerateCrissCross(t *testing.T) {
+	words := []string{"Photosynthesis", "chlorophyll", "oxygen", "carbon", "glucose", "sunlight", "water", "energy", "leaf", "stomata", "root", "xylem"}
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{Words: words})
+	assert.NoError(t, err)
+
+	placed := []string{}
+	for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+		placed = append(placed, string(word.GetValue()))
+	}
+	expected := []string{}
+	for _, word := range words {
+		expected = append(expected, strings.ToLower(word))
+	}
+	assert.ElementsMatch(t, expected, placed)
+	assert.Empty(t, result.Crossword.Validate(crossword.LayoutRules{}))
+
+	replayed, err := crossword.NewCrossword(crossword.CrosswordConfig{Words: words, Seed: result.Seed})
+	assert.NoError(t, err)
+	assert.Equal(t, result.Crossword.String(), replayed.Crossword.String())
+
+	bounded, err := crossword.NewCrossword(crossword.CrosswordConfig{Words: words, Rows: 16, Cols: 16})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, bounded.Crossword.Rows(), 16)
+	assert.LessOrEqual(t, bounded.Crossword.Columns(), 16)
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{Words: []string{"abc", "xyz"}})
+	assert.ErrorIs(t, err, crossword.ErrNoInterlock)
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{Words: []string{"abc", "abc"}})
+	assert.Error(t, err)
+}
+
+func TestGenerateArrowword(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	for _, name := range crossword.ShaperNames() {
+		shaper, err := crossword.ShaperByName(name)
+		assert.NoError(t, err)
+		if _, barred := shaper.(crossword.BarShaper); barred {
+			continue
+		}
+		t.Run(fmt.Sprintf("Shape=%s", name), func(t *testing.T) {
+			result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+				Rows:      11,
+				Cols:      11,
+				Threads:   4,
+				WordDict:  wordDict,
+				Shaper:    shaper,
+				Arrowword: true,
+			})
+
+			assert.NoError(t, err)
+			c := result.Crossword
+			assert.True(t, c.IsArrowword())
+			assert.Empty(t, c.Validate(crossword.LayoutRules{}))
+			for word := crossword.RowWord(c); word != nil; word = word.Next() {
+				clue := crossword.CrosswordLetterAt(c, word.Row(), word.Column()-1)
+				assert.NotZero(t, clue.Arrow()&crossword.ArrowRight)
+			}
+			for word := crossword.ColumnWord(c); word != nil; word = word.Next() {
+				clue := crossword.CrosswordLetterAt(c, word.Row()-1, word.Column())
+				assert.NotZero(t, clue.Arrow()&crossword.ArrowDown)
+			}
+		})
+	}
+}
+
+func TestGenerateBarredCrossword(t *testing.T) {
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     9,
+		Cols:     9,
+		Threads:  4,
+		WordDict: dictionary.NewWordDictionary(),
+		Shaper:   crossword.BarredShaper{},
+	})
+
+	assert.NoError(t, err)
+	c := result.Crossword
+	assert.True(t, c.IsBarred())
+	for word := crossword.RowWord(c); word != nil; word = word.Next() {
+		assert.GreaterOrEqual(t, word.GetLength(), 3)
+		assert.LessOrEqual(t, word.GetLength(), 5)
+		if end := word.Column() + word.GetLength() - 1; end < c.Columns()-1 {
+			last := crossword.CrosswordLetterAt(c, word.Row(), end)
+			assert.NotZero(t, last.Bars()&crossword.BarRight)
+		}
+	}
+	for letter := crossword.CrosswordLetter(c); letter != nil; letter = letter.Next() {
+		assert.False(t, letter.IsBlank())
+	}
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:      9,
+		Cols:      9,
+		Shaper:    crossword.BarredShaper{},
+		Arrowword: true,
+	})
+	assert.Error(t, err)
+}
+
+func TestBarsSeparateWords(t *testing.T) {
+	c, err := crossword.ParseCrossword("abcdef\nghijkl\n")
+	assert.NoError(t, err)
+	c.AddBar(0, 2, crossword.BarRight)
+	c.AddBar(1, 2, crossword.BarRight)
+	c.AddBar(0, 0, crossword.BarBottom)
+
+	words := []string{}
+	for word := crossword.Word(c); word != nil; word = word.Next() {
+		words = append(words, string(word.GetValue()))
+	}
+	assert.Equal(t, []string{"abc", "def", "ghi", "jkl", "bh", "ci", "dj", "ek", "fl"}, words)
+}
+
+func TestParseCrossword(t *testing.T) {
+	grid := "ab.\n_.C\n"
+	c, err := crossword.ParseCrossword(grid)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, c.Rows())
+	assert.Equal(t, 3, c.Columns())
+	assert.True(t, crossword.CrosswordLetterAt(c, 0, 2).IsBlank())
+	assert.True(t, crossword.CrosswordLetterAt(c, 1, 0).IsEmpty())
+	assert.Equal(t, byte('c'), crossword.CrosswordLetterAt(c, 1, 2).GetValue())
+	assert.Equal(t, "ab.\n_.c\n", c.String())
+
+	_, err = crossword.ParseCrossword("abc\nab")
+	assert.Error(t, err)
+	_, err = crossword.ParseCrossword("a!")
+	assert.Error(t, err)
+
+	c, err = crossword.ParseCrossword("12\n3.")
+	assert.NoError(t, err)
+	assert.Equal(t, "12\n3.\n", c.String())
+
+	c, err = crossword.ParseCrossword("#ab\ncd#")
+	assert.NoError(t, err)
+	assert.True(t, c.HasMask())
+	assert.True(t, crossword.CrosswordLetterAt(c, 0, 0).IsMasked())
+	assert.True(t, crossword.CrosswordLetterAt(c, 0, 0).IsBlank())
+	assert.False(t, crossword.CrosswordLetterAt(c, 0, 1).IsMasked())
+	assert.Equal(t, "#ab\ncd#\n", c.String())
+	assert.Equal(t, "#__\n__#\n", c.Layout())
+	words := []string{}
+	for w := crossword.Word(c); w != nil; w = w.Next() {
+		words = append(words, string(w.GetValue()))
+	}
+	assert.Equal(t, []string{"ab", "cd", "ad"}, words)
+}
+
+func TestOutlines(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	for _, name := range crossword.OutlineNames() {
+		outline, err := crossword.OutlineByName(name)
+		assert.NoError(t, err)
+		for _, shape := range []string{"classic", "barred"} {
+			shaper, err := crossword.ShaperByName(shape)
+			assert.NoError(t, err)
+			t.Run(fmt.Sprintf("Outline=%s_Shape=%s", name, shape), func(t *testing.T) {
+				result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+					Rows:     13,
+					Cols:     13,
+					Threads:  4,
+					WordDict: wordDict,
+					Shaper:   shaper,
+					Outline:  outline,
+				})
+				assert.NoError(t, err)
+
+				masked := 0
+				outside := outline.Outside(13, 13)
+				for letter := crossword.CrosswordLetter(result.Crossword); letter != nil; letter = letter.Next() {
+					assert.Equal(t, outside[letter.Row()*13+letter.Column()], letter.IsMasked())
+					if letter.IsMasked() {
+						assert.True(t, letter.IsBlank())
+						masked++
+					}
+				}
+				assert.Positive(t, masked)
+				assert.Equal(t, masked, crossword.Stats(result.Crossword).Masked)
+				for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+					assert.True(t, wordDict.Contains(string(word.GetValue())))
+				}
+			})
+		}
+	}
+
+	_, err := crossword.OutlineByName("unknown")
+	assert.Error(t, err)
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:      9,
+		Cols:      9,
+		Threads:   4,
+		WordDict:  wordDict,
+		Outline:   crossword.HeartOutline{},
+		Arrowword: true,
+	})
+	assert.EqualError(t, err, "an arrowword can't have an outline")
+}
+
+func TestOutlinesOfSmallGrids(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	for _, name := range crossword.OutlineNames() {
+		outline, err := crossword.OutlineByName(name)
+		assert.NoError(t, err)
+		for size := 3; size <= 6; size++ {
+			for _, shape := range []string{"classic", "barred"} {
+				shaper, err := crossword.ShaperByName(shape)
+				assert.NoError(t, err)
+				t.Run(fmt.Sprintf("Outline=%s_Size=%d_Shape=%s", name, size, shape), func(t *testing.T) {
+					result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+						Rows:     size,
+						Cols:     size,
+						Threads:  4,
+						WordDict: wordDict,
+						Shaper:   shaper,
+						Outline:  outline,
+						Timeout:  5 * time.Second,
+					})
+					// outlines too small to hold words across and down have no
+					// layout
+					if errors.Is(err, crossword.ErrNoLayout) {
+						return
+					}
+					assert.NoError(t, err)
+					stats := crossword.Stats(result.Crossword)
+					assert.Positive(t, stats.Across)
+					assert.Positive(t, stats.Down)
+					assert.LessOrEqual(t, stats.BlankRatio, 0.5)
+				})
+			}
+		}
+	}
+
+	for _, name := range []string{"diamond", "star"} {
+		outline, err := crossword.OutlineByName(name)
+		assert.NoError(t, err)
+		_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+			Rows:     3,
+			Cols:     3,
+			Threads:  4,
+			WordDict: wordDict,
+			Outline:  outline,
+		})
+		assert.ErrorIs(t, err, crossword.ErrNoLayout)
+	}
+}
+
+func TestParseOutline(t *testing.T) {
+	outline, err := crossword.ParseOutline("#.\n..")
+	assert.NoError(t, err)
+	rows, columns := outline.Size()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 2, columns)
+	assert.Equal(t, []bool{
+		true, true, false, false,
+		true, true, false, false,
+		false, false, false, false,
+		false, false, false, false,
+	}, outline.Outside(4, 4))
+
+	_, err = crossword.ParseOutline("ab\ncd")
+	assert.Error(t, err)
+}
+
+func TestTransformations(t *testing.T) {
+	c, err := crossword.ParseCrossword("ab.\ncde")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ac\nbd\n.e\n", c.Transpose().String())
+	assert.Equal(t, "ca\ndb\ne.\n", c.Rotate(1).String())
+	assert.Equal(t, "edc\n.ba\n", c.Rotate(2).String())
+	assert.Equal(t, ".e\nbd\nac\n", c.Rotate(3).String())
+	assert.Equal(t, c.Rotate(3).String(), c.Rotate(-1).String())
+	assert.Equal(t, c.String(), c.Rotate(4).String())
+	assert.Equal(t, ".ba\nedc\n", c.MirrorHorizontally().String())
+	assert.Equal(t, "cde\nab.\n", c.MirrorVertically().String())
+	assert.Equal(t, "ab\ncd\n", c.Crop(0, 0, 2, 2).String())
+	assert.Equal(t, "...\n.ab\n.cd\n", c.Crop(-1, -1, 3, 3).String())
+	assert.PanicsWithValue(t, "invalid rows: 0", func() { c.Crop(0, 0, 0, 2) })
+	assert.PanicsWithValue(t, "invalid columns: -1", func() { c.Crop(0, 0, 2, -1) })
+
+	// across words become down words
+	words := func(c *crossword.Crossword) []string {
+		words := []string{}
+		for w := crossword.Word(c); w != nil; w = w.Next() {
+			words = append(words, string(w.GetValue()))
+		}
+		return words
+	}
+	assert.Equal(t, []string{"ab", "cde", "ac", "bd"}, words(c))
+	assert.Equal(t, []string{"ac", "bd", "ab", "cde"}, words(c.Transpose()))
+
+	t.Run("masked squares", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("#ab\ncd#")
+		assert.NoError(t, err)
+		assert.Equal(t, "#c\nad\nb#\n", c.Transpose().String())
+		assert.Equal(t, "#ab#\ncd##\n", c.Crop(0, 0, 2, 4).String())
+	})
+
+	t.Run("bars", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("abcd\nefgh")
+		assert.NoError(t, err)
+		c.AddBar(0, 1, crossword.BarRight)
+		c.AddBar(0, 3, crossword.BarBottom)
+		assert.Equal(t, []string{"ab", "cd", "efgh", "ae", "bf", "cg"}, words(c))
+
+		transposed := c.Transpose()
+		assert.Equal(t, crossword.BarBottom, crossword.CrosswordLetterAt(transposed, 1, 0).Bars())
+		assert.Equal(t, crossword.BarRight, crossword.CrosswordLetterAt(transposed, 3, 0).Bars())
+		assert.Equal(t, []string{"ae", "bf", "cg", "ab", "cd", "efgh"}, words(transposed))
+
+		mirrored := c.MirrorHorizontally()
+		assert.Equal(t, crossword.BarRight, crossword.CrosswordLetterAt(mirrored, 0, 1).Bars())
+		assert.Equal(t, crossword.BarBottom, crossword.CrosswordLetterAt(mirrored, 0, 0).Bars())
+		assert.Equal(t, []string{"dc", "ba", "hgfe", "cg", "bf", "ae"}, words(mirrored))
+	})
+
+	t.Run("clue squares", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("...\n.ab\n.cd")
+		assert.NoError(t, err)
+		c.MarkClueSquares()
+
+		rotated := c.Rotate(2)
+		assert.True(t, rotated.IsArrowword())
+		assert.Equal(t, "dc.\nba.\n...\n", rotated.String())
+		assert.False(t, crossword.CrosswordLetterAt(rotated, 2, 2).IsClue())
+		assert.Len(t, rotated.Validate(crossword.LayoutRules{}), 4)
+
+		transposed := c.Transpose()
+		assert.Equal(t, crossword.ArrowRight, crossword.CrosswordLetterAt(transposed, 1, 0).Arrow())
+		assert.Empty(t, transposed.Validate(crossword.LayoutRules{}))
+	})
+}
+
+func TestCompare(t *testing.T) {
+	solution, err := crossword.ParseCrossword("cat\na.o\nrow")
+	assert.NoError(t, err)
+
+	clone := solution.Clone()
+	assert.True(t, clone.Equal(solution))
+	assert.Equal(t, solution.Fingerprint(), clone.Fingerprint())
+
+	// changing the clone leaves the original alone
+	crossword.CrosswordLetterAt(clone, 0, 1).SetValue('u')
+	assert.Equal(t, "cat\na.o\nrow\n", solution.String())
+	assert.False(t, clone.Equal(solution))
+	assert.NotEqual(t, solution.Fingerprint(), clone.Fingerprint())
+
+	t.Run("diff", func(t *testing.T) {
+		attempt, err := crossword.ParseCrossword("c_t\na.o\nraw")
+		assert.NoError(t, err)
+
+		d, err := crossword.Diff(solution, attempt)
+		assert.NoError(t, err)
+		assert.False(t, d.IsEmpty())
+		assert.Equal(t, []crossword.CellChange{
+			{Row: 0, Column: 1, Before: 'a', After: 0},
+			{Row: 2, Column: 1, Before: 'o', After: 'a'},
+		}, d.Cells)
+		assert.Equal(t, []crossword.EntryChange{
+			{Row: 0, Column: 0, Across: true, Before: "cat", After: "c_t"},
+			{Row: 2, Column: 0, Across: true, Before: "row", After: "raw"},
+		}, d.Entries)
+
+		d, err = crossword.Diff(solution, solution.Clone())
+		assert.NoError(t, err)
+		assert.True(t, d.IsEmpty())
+	})
+
+	t.Run("words only in one crossword", func(t *testing.T) {
+		other, err := crossword.ParseCrossword("ca.\na.o\nrow")
+		assert.NoError(t, err)
+
+		d, err := crossword.Diff(solution, other)
+		assert.NoError(t, err)
+		assert.Equal(t, []crossword.EntryChange{
+			{Row: 0, Column: 0, Across: true, Before: "cat", After: "ca"},
+			{Row: 0, Column: 2, Across: false, Before: "tow", After: ""},
+			{Row: 1, Column: 2, Across: false, Before: "", After: "ow"},
+		}, d.Entries)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		barred := solution.Clone()
+		barred.AddBar(0, 0, crossword.BarRight)
+		assert.False(t, barred.Equal(solution))
+		assert.NotEqual(t, solution.Fingerprint(), barred.Fingerprint())
+
+		masked := solution.Clone()
+		masked.AddMask(1, 1)
+		assert.False(t, masked.Equal(solution))
+		assert.NotEqual(t, solution.Fingerprint(), masked.Fingerprint())
+		assert.True(t, masked.Equal(masked.Clone()))
+	})
+
+	t.Run("different sizes", func(t *testing.T) {
+		_, err := crossword.Diff(solution, solution.Transpose().Crop(0, 0, 2, 3))
+		assert.Error(t, err)
+		assert.False(t, solution.Equal(solution.Crop(0, 0, 2, 3)))
+	})
+}
+
+func TestStats(t *testing.T) {
+	grid, err := crossword.ParseCrossword(`
+		cat
+		a.o
+		two
+	`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, crossword.Statistics{
+		Rows:          3,
+		Columns:       3,
+		Words:         4,
+		Across:        2,
+		Down:          2,
+		Lengths:       map[int]int{3: 4},
+		AverageLength: 3,
+		Longest:       []string{"cat", "two", "too"},
+		Blanks:        1,
+		BlankRatio:    1.0 / 9,
+		Checked:       4,
+		Unchecked:     4,
+		Letters:       map[string]int{"c": 1, "a": 2, "t": 2, "o": 2, "w": 1},
+		ScrabbleScore: 13,
+	}, crossword.Stats(grid))
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("connected and fully checked", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("ab\ncd")
+		assert.NoError(t, err)
+		assert.Empty(t, c.Validate(crossword.LayoutRules{LimitUnchecked: true}))
+	})
+
+	t.Run("disconnected region", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("abc\nd..\n...\nef.")
+		assert.NoError(t, err)
+		violations := c.Validate(crossword.LayoutRules{})
+		assert.Len(t, violations, 1)
+		assert.Equal(t, crossword.DisconnectedRegion, violations[0].Kind)
+		assert.Equal(t, 3, violations[0].Row)
+		assert.Equal(t, 0, violations[0].Column)
+	})
+
+	t.Run("too few letters", func(t *testing.T) {
+		for _, grid := range []string{"...\n...", "ab.\n...", "a.\nb."} {
+			c, err := crossword.ParseCrossword(grid)
+			assert.NoError(t, err)
+			violations := c.Validate(crossword.LayoutRules{})
+			assert.Len(t, violations, 1)
+			assert.Equal(t, crossword.TooFewLetters, violations[0].Kind)
+		}
+	})
+
+	t.Run("unchecked cells", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("abc\nd.e\nfgh")
+		assert.NoError(t, err)
+		assert.Empty(t, c.Validate(crossword.LayoutRules{}))
+		assert.Empty(t, c.Validate(crossword.LayoutRules{LimitUnchecked: true, MaxUncheckedRatio: 0.5}))
+
+		violations := c.Validate(crossword.LayoutRules{LimitUnchecked: true})
+		assert.Len(t, violations, 1)
+		assert.Equal(t, crossword.TooManyUncheckedCells, violations[0].Kind)
+	})
+
+	t.Run("unanchored arrowword word", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("...\n.ab\n.cd\n")
+		assert.NoError(t, err)
+		c.MarkClueSquares()
+		assert.Empty(t, c.Validate(crossword.LayoutRules{}))
+		assert.False(t, crossword.CrosswordLetterAt(c, 0, 0).IsClue())
+		assert.Equal(t, crossword.ArrowRight, crossword.CrosswordLetterAt(c, 1, 0).Arrow())
+		assert.Equal(t, crossword.ArrowDown, crossword.CrosswordLetterAt(c, 0, 1).Arrow())
+
+		c, err = crossword.ParseCrossword("ab\ncd")
+		assert.NoError(t, err)
+		c.MarkClueSquares()
+		violations := c.Validate(crossword.LayoutRules{})
+		assert.Len(t, violations, 4)
+		assert.Equal(t, crossword.UnanchoredWord, violations[0].Kind)
+
+		// layouts are validated before they are filled
+		c, err = crossword.ParseCrossword("__\n__")
+		assert.NoError(t, err)
+		c.MarkClueSquares()
+		violations = c.Validate(crossword.LayoutRules{})
+		assert.Len(t, violations, 4)
+		assert.Equal(t, "the horizontal word of 2 letters has no clue square", violations[0].Message)
+	})
+
+	t.Run("word lengths", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("abcd\ne..f\ngh.i")
+		assert.NoError(t, err)
+		assert.Empty(t, c.Validate(crossword.LayoutRules{MinWordLength: 2, MaxWordLength: 4}))
+
+		violations := c.Validate(crossword.LayoutRules{MinWordLength: 3, MaxWordLength: 3})
+		assert.Len(t, violations, 2)
+		assert.Equal(t, crossword.LongWord, violations[0].Kind)
+		assert.Equal(t, 0, violations[0].Row)
+		assert.Equal(t, 0, violations[0].Column)
+		assert.Equal(t, crossword.ShortWord, violations[1].Kind)
+		assert.Equal(t, 2, violations[1].Row)
+		assert.Equal(t, 0, violations[1].Column)
+	})
+
+	t.Run("blank density", func(t *testing.T) {
+		c, err := crossword.ParseCrossword("abc\nd.e\nfgh")
+		assert.NoError(t, err)
+		assert.Empty(t, c.Validate(crossword.LayoutRules{MinDensity: 0.1, MaxDensity: 0.2}))
+
+		violations := c.Validate(crossword.LayoutRules{MinDensity: 0.15, MaxDensity: 0.2})
+		assert.Len(t, violations, 1)
+		assert.Equal(t, crossword.BlankDensity, violations[0].Kind)
+		assert.Equal(t, 1, violations[0].Row)
+		assert.Equal(t, 1, violations[0].Column)
+		assert.Equal(t, "1 of 9 squares are blank (11%, expected 15-20%)", violations[0].Message)
+	})
+}
+
+func TestParseDensity(t *testing.T) {
+	minDensity, maxDensity, err := crossword.ParseDensity("15-20")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.15, minDensity)
+	assert.Equal(t, 0.2, maxDensity)
+
+	for _, value := range []string{"15", "a-20", "20-15", "0-0", "-5-10", "10-120"} {
+		_, _, err := crossword.ParseDensity(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGenerateCrosswordWithLayoutRules(t *testing.T) {
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:              9,
+		Cols:              9,
+		Threads:           4,
+		WordDict:          dictionary.NewWordDictionary(),
+		LimitUnchecked:    true,
+		MaxUncheckedRatio: 0.65,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Crossword.Validate(crossword.LayoutRules{LimitUnchecked: true, MaxUncheckedRatio: 0.65}))
+
+	_, err = crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:           9,
+		Cols:           9,
+		Threads:        4,
+		WordDict:       dictionary.NewWordDictionary(),
+		LimitUnchecked: true,
+	})
+	assert.ErrorIs(t, err, crossword.ErrNoLayout)
+}
+
+func TestGenerateCrosswordWithWordLengths(t *testing.T) {
+	for _, shaper := range []crossword.Shaper{crossword.ClassicShaper{}, crossword.OpenShaper{}, crossword.StaircaseShaper{}} {
+		result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+			Rows:          9,
+			Cols:          9,
+			Threads:       4,
+			WordDict:      dictionary.NewWordDictionary(),
+			Shaper:        shaper,
+			MinWordLength: 3,
+			MaxWordLength: 7,
+		})
+		assert.NoError(t, err)
+		for w := crossword.Word(result.Crossword); w != nil; w = w.Next() {
+			assert.GreaterOrEqual(t, len(w.GetValue()), 3)
+			assert.LessOrEqual(t, len(w.GetValue()), 7)
+		}
+	}
+
+	_, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:          9,
+		Cols:          9,
+		Threads:       4,
+		WordDict:      dictionary.NewWordDictionary(),
+		MinWordLength: 5,
+		MaxWordLength: 4,
+	})
+	assert.EqualError(t, err, "invalid word length range [5, 4]")
+}
+
+func TestGenerateCrosswordWithDensity(t *testing.T) {
+	for _, density := range [][2]float64{{0.2, 0.25}, {0.35, 0.4}} {
+		result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+			Rows:       9,
+			Cols:       9,
+			Threads:    4,
+			WordDict:   dictionary.NewWordDictionary(),
+			MinDensity: density[0],
+			MaxDensity: density[1],
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, density[0], result.MinDensity)
+		assert.Equal(t, density[1], result.MaxDensity)
+		ratio := crossword.Stats(result.Crossword).BlankRatio
+		assert.GreaterOrEqual(t, ratio, density[0])
+		assert.LessOrEqual(t, ratio, density[1])
+	}
+
+	_, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:       9,
+		Cols:       9,
+		Threads:    4,
+		WordDict:   dictionary.NewWordDictionary(),
+		Shaper:     crossword.BarredShaper{},
+		MinDensity: 0.2,
+	})
+	assert.EqualError(t, err, "a barred crossword has no blank squares")
+}
+
+func TestGenerateCrosswordWithLowDensity(t *testing.T) {
+	// a standard grid with 15-20% blank squares is rarely fillable, so the
+	// density range is raised until it is
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:       13,
+		Cols:       13,
+		Threads:    4,
+		WordDict:   dictionary.NewWordDictionary(),
+		MinDensity: 0.15,
+		MaxDensity: 0.2,
+		Timeout:    30 * time.Second,
+	})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, result.MinDensity, 0.15)
+	assert.LessOrEqual(t, result.MaxDensity, 0.4+1e-9)
+	assert.InDelta(t, 0.05, result.MaxDensity-result.MinDensity, 1e-9)
+	ratio := crossword.Stats(result.Crossword).BlankRatio
+	assert.GreaterOrEqual(t, ratio, result.MinDensity-1e-9)
+	assert.LessOrEqual(t, ratio, result.MaxDensity+1e-9)
+}
+
+func TestGenerateCrosswordTimeout(t *testing.T) {
+	_, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     23,
+		Cols:     23,
+		Threads:  1,
+		WordDict: dictionary.NewWordDictionary(),
+		Timeout:  time.Nanosecond,
+	})
+	assert.ErrorIs(t, err, crossword.ErrTimeout)
+}
+
+// BenchmarkGenerateLargeCrossword generates a Sunday-size 21x21 crossword. The
+// time budget of every generation defaults to 30s and can be configured with
+// the CROSSWORD_BENCH_BUDGET environment variable (e.g. "10s").
+func BenchmarkGenerateLargeCrossword(b *testing.B) {
+	budget := 30 * time.Second
+	if value := os.Getenv("CROSSWORD_BENCH_BUDGET"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			b.Fatalf("invalid CROSSWORD_BENCH_BUDGET: %v", err)
+		}
+		budget = parsed
+	}
+
+	wordDict := dictionary.NewWordDictionary()
+	for b.Loop() {
+		result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+			Rows:     21,
+			Cols:     21,
+			Threads:  runtime.NumCPU(),
+			WordDict: wordDict,
+			Timeout:  budget,
+		})
+		if err != nil {
+			b.Fatalf("21x21 crossword not filled within %s: %v", budget, err)
+		}
+		if !result.Crossword.IsFilled() {
+			b.Fatal("21x21 crossword is not filled")
+		}
+	}
+}
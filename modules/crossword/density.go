@@ -0,0 +1,263 @@
+package crossword
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// densityTimeout bounds the time spent generating a crossword within a
+// density range when no Timeout is configured, since a grid with too few blank
+// squares may never be filled.
+const densityTimeout = 60 * time.Second
+
+// densityStepTimeout is the time spent filling a grid within a density range
+// before falling back to a higher one, when no Timeout is configured.
+const densityStepTimeout = 10 * time.Second
+
+// densityFallbackStep is the proportion of blank squares added to both bounds
+// of a density range when falling back to a higher one, and
+// maxFallbackDensity the highest upper bound fallen back to.
+const (
+	densityFallbackStep = 0.05
+	maxFallbackDensity  = 0.4
+)
+
+// densityBounds is a range of proportions of blank squares, zero meaning no
+// bound.
+type densityBounds struct {
+	min, max float64
+}
+
+// densityRanges returns the density ranges a grid is filled within in turn:
+// the configured one, then ranges raised by densityFallbackStep until their
+// upper bound reaches maxFallbackDensity. A range without upper bound has no
+// fallback.
+func (config CrosswordConfig) densityRanges() []densityBounds {
+	ranges := []densityBounds{{config.MinDensity, config.MaxDensity}}
+	if config.MaxDensity == 0 {
+		return ranges
+	}
+	// the bounds are computed from the configured ones to avoid accumulating
+	// rounding errors
+	for i := 1; ranges[len(ranges)-1].max < maxFallbackDensity-1e-9; i++ {
+		step := float64(i) * densityFallbackStep
+		bounds := densityBounds{max: min(config.MaxDensity+step, 1)}
+		if config.MinDensity > 0 {
+			bounds.min = config.MinDensity + step
+		}
+		ranges = append(ranges, bounds)
+	}
+	return ranges
+}
+
+// densityStepTimeout returns the time spent on every density range but the
+// last one, out of the given number of ranges.
+func (config CrosswordConfig) densityStepTimeout(ranges int) time.Duration {
+	if config.Timeout > 0 {
+		return config.Timeout / time.Duration(ranges)
+	}
+	return densityStepTimeout
+}
+
+// fallsBackDensity reports whether a grid that couldn't be filled within a
+// density range because of the error should be filled within a higher one.
+func fallsBackDensity(err error) bool {
+	return fallsBack(err) || errors.Is(err, ErrNoLayout)
+}
+
+// ParseDensity parses a density range written in percents, such as "15-20",
+// into the proportions of blank squares used by CrosswordConfig.MinDensity and
+// CrosswordConfig.MaxDensity.
+func ParseDensity(value string) (float64, float64, error) {
+	low, high, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid density %q, expected a range such as 15-20", value)
+	}
+	minPercent, err := strconv.ParseFloat(strings.TrimSpace(low), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid density %q, expected a range such as 15-20", value)
+	}
+	maxPercent, err := strconv.ParseFloat(strings.TrimSpace(high), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid density %q, expected a range such as 15-20", value)
+	}
+	if minPercent < 0 || maxPercent <= 0 || maxPercent > 100 || minPercent > maxPercent {
+		return 0, 0, fmt.Errorf("invalid density %q, expected percents from 0 to 100", value)
+	}
+	return minPercent / 100, maxPercent / 100, nil
+}
+
+// boundsDensity reports whether the rules bound the proportion of blank
+// squares.
+func (rules LayoutRules) boundsDensity() bool {
+	return rules.MinDensity > 0 || rules.MaxDensity > 0
+}
+
+// shapeDensity adds or removes blank squares at random until the proportion
+// of blank squares is within the density range of the rules, or no square can
+// change without breaking the length of a word, isolating a letter or cutting
+// the grid in two. The squares outside the playing area stay blank and don't
+// count.
+func shapeDensity(data []byte, outside []bool, rows, columns int, rules LayoutRules, random *rand.Rand) {
+	minLength := max(rules.MinWordLength, 2)
+	maxLength := rules.MaxWordLength
+	if maxLength == 0 {
+		maxLength = openMaxLength(rows, columns)
+	}
+	masked := func(pos int) bool {
+		return outside != nil && outside[pos]
+	}
+	blanks, squares := 0, 0
+	for pos, square := range data {
+		if !masked(pos) {
+			squares++
+			if square == Blank {
+				blanks++
+			}
+		}
+	}
+	minBlanks, maxBlanks := densityBlanks(squares, rules)
+
+	order := random.Perm(len(data))
+	for blanks < minBlanks || blanks > maxBlanks {
+		// the blank squares joining the shortest words are filled first, long
+		// words making grids much harder to fill
+		best, bestLength := -1, 0
+		for _, pos := range order {
+			if blanks > maxBlanks && data[pos] == Blank && !masked(pos) {
+				if length := filledLength(data, rows, columns, pos, minLength, maxLength); length > 0 && (best == -1 || length < bestLength) {
+					best, bestLength = pos, length
+				}
+			}
+			if blanks < minBlanks && data[pos] != Blank && canBlank(data, rows, columns, pos, minLength) {
+				best = pos
+				break
+			}
+		}
+		if best == -1 {
+			return
+		}
+		if data[best] == Blank {
+			data[best] = 0
+			blanks--
+		} else {
+			data[best] = Blank
+			blanks++
+		}
+		random.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+}
+
+// densityBlanks returns the lowest and highest numbers of blank squares of a
+// grid of the given number of squares within the density range of the rules.
+func densityBlanks(squares int, rules LayoutRules) (int, int) {
+	// the margins absorb the rounding errors of percents
+	minBlanks := int(math.Ceil(rules.MinDensity*float64(squares) - 1e-9))
+	maxBlanks := squares
+	if rules.MaxDensity > 0 {
+		maxBlanks = int(math.Floor(rules.MaxDensity*float64(squares) + 1e-9))
+	}
+	return minBlanks, maxBlanks
+}
+
+// filledLength returns the length of the longest word the blank square at pos
+// would join if it held a letter, or 0 if it can't hold one: the letter must
+// join a word of minLength to maxLength letters across or down and not make
+// any word longer or shorter than that.
+func filledLength(data []byte, rows, columns, pos, minLength, maxLength int) int {
+	longest := 0
+	for _, across := range []bool{true, false} {
+		before, after := lettersAround(data, rows, columns, pos, across)
+		length := before + 1 + after
+		if length == 1 {
+			continue
+		}
+		if length < minLength || length > maxLength {
+			return 0
+		}
+		longest = max(longest, length)
+	}
+	return longest
+}
+
+// canBlank reports whether the letter at pos can be blanked out, leaving words
+// of at least minLength letters on both sides across and down, and the letters
+// of the grid connected.
+func canBlank(data []byte, rows, columns, pos, minLength int) bool {
+	for _, across := range []bool{true, false} {
+		before, after := lettersAround(data, rows, columns, pos, across)
+		if (before > 0 && before < minLength) || (after > 0 && after < minLength) {
+			return false
+		}
+	}
+	square := data[pos]
+	data[pos] = Blank
+	_, sizes := regions(rows, columns, func(pos int) bool {
+		return data[pos] != Blank
+	})
+	data[pos] = square
+	return len(sizes) <= 2
+}
+
+// lettersAround returns the numbers of consecutive letters before and after
+// the square at pos, across or down.
+func lettersAround(data []byte, rows, columns, pos int, across bool) (int, int) {
+	row, column := pos/columns, pos%columns
+	step, before, after := columns, row, rows-1-row
+	if across {
+		step, before, after = 1, column, columns-1-column
+	}
+	count := func(direction, limit int) int {
+		n := 0
+		for n < limit && data[pos+direction*(n+1)*step] != Blank {
+			n++
+		}
+		return n
+	}
+	return count(-1, before), count(1, after)
+}
+
+// densityViolations reports a grid whose proportion of blank squares is out
+// of the density range.
+func (c *Crossword) densityViolations(rules LayoutRules) []Violation {
+	if !rules.boundsDensity() {
+		return []Violation{}
+	}
+	blanks, first := 0, -1
+	for pos, square := range c.data {
+		if square == Blank && !c.isMasked(pos) {
+			blanks++
+			if first == -1 {
+				first = pos
+			}
+		}
+	}
+	squares := c.squares()
+	minBlanks, maxBlanks := densityBlanks(squares, rules)
+	if blanks >= minBlanks && blanks <= maxBlanks {
+		return []Violation{}
+	}
+	first = max(first, 0)
+	return []Violation{{
+		Kind:   BlankDensity,
+		Row:    first / c.columns,
+		Column: first % c.columns,
+		Message: fmt.Sprintf("%d of %d squares are blank (%.0f%%, expected %s)", blanks, squares,
+			100*float64(blanks)/float64(squares), densityRange(rules)),
+	}}
+}
+
+// densityRange writes the density range of the rules in percents.
+func densityRange(rules LayoutRules) string {
+	if rules.MaxDensity == 0 {
+		return fmt.Sprintf("at least %.0f%%", 100*rules.MinDensity)
+	}
+	return fmt.Sprintf("%.0f-%.0f%%", 100*rules.MinDensity, 100*rules.MaxDensity)
+}
@@ -0,0 +1,107 @@
+package crossword
+
+import (
+	"fmt"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// Difficulty rates how hard a crossword is to solve. Score and its components
+// range from 0 for the easiest crosswords to 1 for the hardest.
+type Difficulty struct {
+	Score float64
+	// Rarity is the average frequency tier of the answers, unranked answers
+	// counting as the rarest. It is only part of the score when Ranked is
+	// true, which needs a dictionary.TieredSource.
+	Rarity float64
+	Ranked bool
+	// Length grows with the average length of the answers, from 3 letters to
+	// 9 letters or more.
+	Length float64
+	// Unchecked is the proportion of letters belonging to a single answer,
+	// which can't be inferred from a crossing answer.
+	Unchecked float64
+	// Openness is the proportion of squares holding a letter.
+	Openness float64
+}
+
+// difficultyWeights are the weights of the rarity, length, unchecked and
+// openness components of the difficulty score.
+var difficultyWeights = [4]float64{0.4, 0.2, 0.2, 0.2}
+
+// The difficulty scores from which a crossword is rated medium and hard.
+const (
+	MediumDifficulty = 0.45
+	HardDifficulty   = 0.6
+)
+
+// Label rates the difficulty as easy, medium or hard.
+func (d Difficulty) Label() string {
+	switch {
+	case d.Score >= HardDifficulty:
+		return "hard"
+	case d.Score >= MediumDifficulty:
+		return "medium"
+	}
+	return "easy"
+}
+
+// String writes the label and the score of the difficulty, noting when the
+// rarity of the answers isn't part of the score.
+func (d Difficulty) String() string {
+	if !d.Ranked {
+		return fmt.Sprintf("%s (%.2f, without word rarity)", d.Label(), d.Score)
+	}
+	return fmt.Sprintf("%s (%.2f)", d.Label(), d.Score)
+}
+
+// RateDifficulty rates the difficulty of a filled crossword. The rarity of its
+// answers is only taken into account if wordDict is a dictionary.TieredSource.
+func RateDifficulty(c *Crossword, wordDict dictionary.WordSource) Difficulty {
+	d := Difficulty{}
+	tiers, ranked := wordDict.(dictionary.TieredSource)
+	d.Ranked = ranked && tiers.Tiers() > 1
+
+	answers, letters, rarity := 0, 0, 0.0
+	for word := Word(c); word != nil; word = word.Next() {
+		answers++
+		letters += word.length
+		if !d.Ranked {
+			continue
+		}
+		tier, ok := tiers.Tier(string(word.GetValue()))
+		if !ok {
+			tier = tiers.Tiers() - 1
+		}
+		rarity += float64(tier) / float64(tiers.Tiers()-1)
+	}
+	squares := 0
+	for _, value := range c.data {
+		if value != Blank {
+			squares++
+		}
+	}
+	if answers == 0 || squares == 0 {
+		return d
+	}
+
+	d.Length = min(max((float64(letters)/float64(answers)-3)/6, 0), 1)
+	d.Unchecked = float64(len(c.uncheckedCells())) / float64(squares)
+	d.Openness = float64(squares) / float64(c.squares())
+
+	components := [4]float64{0, d.Length, d.Unchecked, d.Openness}
+	if d.Ranked {
+		d.Rarity = rarity / float64(answers)
+		components[0] = d.Rarity
+	}
+	total := 0.0
+	for i, weight := range difficultyWeights {
+		if i == 0 && !d.Ranked {
+			continue
+		}
+		d.Score += weight * components[i]
+		total += weight
+	}
+	d.Score /= total
+	return d
+}
@@ -0,0 +1,102 @@
+package crossword
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Empty is the character used for empty squares by ParseCrossword and String.
+const Empty = '_'
+
+// ParseCrossword reads a crossword written one row per line, using letters, or
+// digits for cross-number puzzles, for filled squares, Blank for blank squares
+// and Empty for empty squares. Leading and trailing whitespace and empty lines
+// are ignored. Masked stands for the squares outside the playing area of a
+// non-rectangular crossword.
+func ParseCrossword(grid string) (*Crossword, error) {
+	lines := []string{}
+	for _, line := range strings.Split(grid, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty crossword")
+	}
+
+	rows, columns := len(lines), len(lines[0])
+	data := make([]byte, 0, rows*columns)
+	var masked []bool
+	for row, line := range lines {
+		if len(line) != columns {
+			return nil, fmt.Errorf("row %d has %d squares, expected %d", row+1, len(line), columns)
+		}
+		for column := range len(line) {
+			square := line[column]
+			switch {
+			case square == Blank:
+				data = append(data, Blank)
+			case square == Empty:
+				data = append(data, 0)
+			case square == Masked:
+				if masked == nil {
+					masked = make([]bool, rows*columns)
+				}
+				masked[len(data)] = true
+				data = append(data, Blank)
+			case square >= 'a' && square <= 'z':
+				data = append(data, square)
+			case square >= 'A' && square <= 'Z':
+				data = append(data, square+'a'-'A')
+			case square >= '0' && square <= '9':
+				data = append(data, square)
+			default:
+				return nil, fmt.Errorf("invalid square %q at row %d, column %d", square, row+1, column+1)
+			}
+		}
+	}
+
+	return &Crossword{
+		rows:    rows,
+		columns: columns,
+		data:    data,
+		masked:  masked,
+	}, nil
+}
+
+// String writes the crossword in the format read by ParseCrossword.
+func (c *Crossword) String() string {
+	var builder strings.Builder
+	for pos, square := range c.data {
+		switch {
+		case c.isMasked(pos):
+			square = Masked
+		case square == 0:
+			square = Empty
+		}
+		builder.WriteByte(square)
+		if (pos+1)%c.columns == 0 {
+			builder.WriteByte('\n')
+		}
+	}
+	return builder.String()
+}
+
+// Layout writes the crossword like String with every letter replaced by
+// Empty, so that crosswords sharing their blank squares have the same layout.
+func (c *Crossword) Layout() string {
+	var builder strings.Builder
+	for pos, square := range c.data {
+		switch {
+		case c.isMasked(pos):
+			square = Masked
+		case square != Blank:
+			square = Empty
+		}
+		builder.WriteByte(square)
+		if (pos+1)%c.columns == 0 {
+			builder.WriteByte('\n')
+		}
+	}
+	return builder.String()
+}
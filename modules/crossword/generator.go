@@ -1,6 +1,7 @@
 package crossword
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math/rand"
@@ -12,36 +13,53 @@ import (
 
 // starting with an empty crossword, try to fill the crossword word by word,
 // starting with the longest ones. if stuck or we ended up creating
-// non-existent words, backtrack and try again.
-func generateCrossword(ctx context.Context, rows, columns int, seed int64, wordDict dictionary.WordDictionary, solvedCrossword chan CrosswordResult) {
+// non-existent words, backtrack and try again. the attempt is abandoned and nil
+// is returned once maxBacktracks backtracks have been made (0 means no limit),
+// if the layout turns out to be impossible to fill or if the context is
+// cancelled.
+func generateCrossword(ctx context.Context, config CrosswordConfig, seed int64, maxBacktracks int) (*Crossword, error) {
 	random := rand.New(rand.NewSource(seed))
-	crossword := newEmptyCrossword(rows, columns, random)
+	crossword, err := newLayout(config, random)
+	if err != nil {
+		return nil, err
+	}
+	if config.Mini {
+		return fillMini(ctx, crossword, config.WordDict, random, maxBacktracks), nil
+	}
+
 	crawler := newCrosswordCrawler(crossword)
+	for _, word := range config.Require {
+		crawler.storeWord(word)
+	}
+	backtracks := 0
 
 	for {
 		// abort if the context is cancelled - a solution has already been found
 		select {
 		case <-ctx.Done():
-			return
+			return nil, nil
 		default:
 		}
 
+		// give up on this layout if it is taking too long to fill
+		if maxBacktracks > 0 && backtracks >= maxBacktracks {
+			return nil, nil
+		}
+
 		// if the whole crossword is filled then a solution has been found
 		if crossword.IsFilled() {
-			select {
-			case solvedCrossword <- newCrosswordResult(crossword, seed):
-				return
-			default:
-				return
-			}
+			return crossword, nil
 		}
 
 		currentWord := crawler.currentWord()
 		currentWordValue := currentWord.GetValue()
 
 		if currentWord.IsFilled() {
-			if !wordDict.Contains(string(currentWordValue)) {
-				crawler.backtrack()
+			if !config.WordDict.Contains(string(currentWordValue)) {
+				backtracks++
+				if !crawler.backtrack() {
+					return nil, nil
+				}
 				continue
 			}
 			crawler.goToNextWord()
@@ -50,27 +68,155 @@ func generateCrossword(ctx context.Context, rows, columns int, seed int64, wordD
 
 		// find possible candidates for the current word based on the current
 		// state of the crossword
-		candidates := wordDict.Candidates(currentWordValue)
+		candidates := config.WordDict.Candidates(currentWordValue)
 		// exclude words that are already in the crossword
 		candidates = slices.DeleteFunc(candidates, func(e int) bool {
-			_, exists := crawler.wordsSoFar[wordDict.AllWords[e]]
+			_, exists := crawler.wordsSoFar[config.WordDict.Word(e)]
 			return exists
 		})
 
-		if len(candidates) == 0 {
-			crawler.backtrack()
-			continue
+		// try candidates at random until one leaves all the crossing words
+		// with a chance of being filled, which prunes dead ends early
+		placed := false
+		for range min(len(candidates), maxCandidateTries) {
+			i := pickCandidate(config.WordDict, candidates, random)
+			candidate := config.WordDict.Word(candidates[i])
+			currentWord.SetValue([]byte(candidate))
+			if crawler.crossingsFillable(config.WordDict) {
+				crawler.pushToStack(currentWordValue)
+				crawler.storeWord(candidate)
+				crawler.goToNextWord()
+				placed = true
+				break
+			}
+			currentWord.SetValue(currentWordValue)
+			candidates[i] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+		}
+
+		if !placed {
+			backtracks++
+			if !crawler.backtrack() {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// maxCandidateTries is the number of candidates tried for a word before
+// backtracking.
+const maxCandidateTries = 10
+
+// pickCandidate returns the position of a random candidate, drawn with a
+// probability proportional to its score if the word source scores its words.
+func pickCandidate(wordDict dictionary.WordSource, candidates []int, random *rand.Rand) int {
+	scorer, ok := wordDict.(dictionary.WordScorer)
+	if !ok {
+		return random.Intn(len(candidates))
+	}
+	total := 0.0
+	for _, candidate := range candidates {
+		total += scorer.Score(candidate)
+	}
+	target := random.Float64() * total
+	for i, candidate := range candidates {
+		if target -= scorer.Score(candidate); target < 0 {
+			return i
 		}
+	}
+	return len(candidates) - 1
+}
 
-		candidate := wordDict.AllWords[candidates[random.Intn(len(candidates))]]
-		crawler.pushToStack(currentWordValue)
-		currentWord.SetValue([]byte(candidate))
-		crawler.storeWord(candidate)
-		crawler.goToNextWord()
+// shuffleCandidates shuffles the candidates at random, the ones with a higher
+// score coming first more often if the word source scores its words.
+func shuffleCandidates(wordDict dictionary.WordSource, candidates []int, random *rand.Rand) {
+	scorer, ok := wordDict.(dictionary.WordScorer)
+	if !ok {
+		random.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		return
+	}
+	// each candidate is given an exponentially distributed key of rate its
+	// score, the smallest keys coming first
+	keys := make(map[int]float64, len(candidates))
+	for _, candidate := range candidates {
+		keys[candidate] = random.ExpFloat64() / scorer.Score(candidate)
 	}
+	slices.SortFunc(candidates, func(a, b int) int {
+		return cmp.Compare(keys[a], keys[b])
+	})
 }
 
-func newEmptyCrossword(rows, columns int, random *rand.Rand) *Crossword {
+// maxLayoutAttempts is the number of layouts tried by newLayout before giving
+// up on finding one that follows the layout rules.
+const maxLayoutAttempts = 10000
+
+// maxShapedBlankRatio is the highest proportion of blank squares of a layout
+// without density range, beyond which too many words were blanked out, to
+// follow word length bounds or an outline, for the layout to be worth filling.
+const maxShapedBlankRatio = 0.5
+
+// newLayout shapes empty crosswords until one follows the layout rules of the
+// configuration, isn't an excluded layout and has room for its required words,
+// which are placed in it. A penalized layout is only returned if no other
+// layout was found.
+func newLayout(config CrosswordConfig, random *rand.Rand) (*Crossword, error) {
+	rules := config.layoutRules()
+	excluded, penalized := map[string]bool{}, map[string]bool{}
+	for _, layout := range config.ExcludeLayouts {
+		excluded[layout] = true
+	}
+	for _, layout := range config.PenalizeLayouts {
+		penalized[layout] = true
+	}
+	valid := false
+	var fallback *Crossword
+	for range maxLayoutAttempts {
+		crossword := newEmptyCrossword(config.Rows, config.Cols, config.shaper(), config.Outline, rules, random)
+		if config.Arrowword {
+			crossword.MarkClueSquares()
+		}
+		if len(crossword.Validate(rules)) != 0 {
+			continue
+		}
+		if rules.MaxDensity == 0 && crossword.blankRatio() > maxShapedBlankRatio {
+			continue
+		}
+		if len(excluded) > 0 || len(penalized) > 0 {
+			layout := crossword.Layout()
+			if excluded[layout] {
+				continue
+			}
+			if penalized[layout] {
+				if fallback == nil {
+					fallback = crossword
+				}
+				continue
+			}
+		}
+		valid = true
+		if placeRequiredWords(crossword, config.Require, config.WordDict, random) {
+			return crossword, nil
+		}
+	}
+	if fallback != nil {
+		valid = true
+		if placeRequiredWords(fallback, config.Require, config.WordDict, random) {
+			return fallback, nil
+		}
+	}
+	if valid {
+		return nil, ErrRequiredWords
+	}
+	return nil, ErrNoLayout
+}
+
+// newEmptyCrossword lays out an empty crossword with the shaper within the
+// outline, if any, blanking out and splitting the words whose length breaks
+// the rules and then adding or removing blank squares to follow their density
+// range, unless the words are separated by bars.
+func newEmptyCrossword(rows, columns int, shaper Shaper, outline Outline, rules LayoutRules, random *rand.Rand) *Crossword {
 	if rows < 1 {
 		panic(fmt.Sprintf("invalid rows: %d", rows))
 	}
@@ -80,22 +226,21 @@ func newEmptyCrossword(rows, columns int, random *rand.Rand) *Crossword {
 
 	data := make([]byte, columns*rows)
 
-	// create blank squares based on specific conditions
-	for i := range rows {
-		for j := range columns {
-			if i%2 == 1 && (i+j)%2 == 0 {
-				data[i*columns+j] = Blank
-			}
-			if i == 0 && j%2 == 0 && random.Float64() < 0.75 {
-				data[j+random.Intn(rows)*columns] = Blank
-			}
+	// create blank squares based on the layout chosen by the shaper, the
+	// squares outside the outline being blank too
+	mask := shaper.Shape(rows, columns, random)
+	var outside []bool
+	if outline != nil {
+		outside = outline.Outside(rows, columns)
+	}
+	for i, blank := range mask {
+		if blank || (outside != nil && outside[i]) {
+			data[i] = Blank
 		}
+	}
 
-		if i%2 == 0 && columns > 7 {
-			if random.Float64() < 0.75 {
-				data[i*columns+random.Intn(columns)] = Blank
-			}
-		}
+	if _, barred := shaper.(BarShaper); !barred && rules.boundsWordLength() {
+		shapeWordLengths(data, rows, columns, rules, random)
 	}
 
 	// replace any single letter words with empty space
@@ -113,15 +258,44 @@ func newEmptyCrossword(rows, columns int, random *rand.Rand) *Crossword {
 		}
 	}
 
-	return &Crossword{
+	if _, barred := shaper.(BarShaper); !barred && rules.boundsDensity() {
+		shapeDensity(data, outside, rows, columns, rules, random)
+	}
+
+	c := &Crossword{
 		rows:    rows,
 		columns: columns,
 		data:    data,
+		masked:  outside,
+	}
+	if barShaper, ok := shaper.(BarShaper); ok {
+		c.bars = barShaper.Bars(mask, rows, columns, random)
+		if outside != nil {
+			// bars can leave letters cut off by the outline out of any word
+			c.blankOrphans()
+		}
+	}
+	return c
+}
+
+// blankOrphans blanks out the letters that don't belong to any word.
+func (c *Crossword) blankOrphans() {
+	words := make([]int, len(c.data))
+	for word := Word(c); word != nil; word = word.Next() {
+		for letter := WordLetter(word); letter != nil; letter = letter.Next() {
+			words[letter.pos]++
+		}
+	}
+	for pos, value := range c.data {
+		if value != Blank && words[pos] == 0 {
+			c.data[pos] = Blank
+		}
 	}
 }
 
 type crosswordCrawler struct {
 	words            []WordRef
+	crossings        [][]int
 	stack            []wordStack
 	wordsSoFar       map[string]struct{}
 	currentWordIndex int
@@ -142,8 +316,10 @@ func newCrosswordCrawler(c *Crossword) *crosswordCrawler {
 	sort.Slice(words, func(i, j int) bool {
 		return words[i].length > words[j].length
 	})
+
 	return &crosswordCrawler{
 		words:            words,
+		crossings:        wordCrossings(c, words),
 		stack:            []wordStack{},
 		wordsSoFar:       make(map[string]struct{}),
 		currentWordIndex: 0,
@@ -152,6 +328,27 @@ func newCrosswordCrawler(c *Crossword) *crosswordCrawler {
 	}
 }
 
+// wordCrossings indexes the words crossing each of the given words.
+func wordCrossings(c *Crossword, words []WordRef) [][]int {
+	wordsAt := make([][]int, len(c.data))
+	for i := range words {
+		for letter := WordLetter(&words[i]); letter != nil; letter = letter.Next() {
+			wordsAt[letter.pos] = append(wordsAt[letter.pos], i)
+		}
+	}
+	crossings := make([][]int, len(words))
+	for _, indexes := range wordsAt {
+		for _, i := range indexes {
+			for _, j := range indexes {
+				if i != j {
+					crossings[i] = append(crossings[i], j)
+				}
+			}
+		}
+	}
+	return crossings
+}
+
 func (c *crosswordCrawler) pushToStack(value []byte) {
 	c.stack = append(c.stack, wordStack{index: c.currentWordIndex, word: value})
 }
@@ -164,11 +361,22 @@ func (c *crosswordCrawler) currentWord() *WordRef {
 	return &c.words[c.currentWordIndex]
 }
 
+// crossingsFillable reports whether all the words crossing the current word
+// are either valid words or can still be completed into one.
+func (c *crosswordCrawler) crossingsFillable(wordDict dictionary.WordSource) bool {
+	return crossingsFillable(c.words, c.crossings[c.currentWordIndex], wordDict)
+}
+
 func (c *crosswordCrawler) goToNextWord() {
 	c.currentWordIndex++
 }
 
-func (c *crosswordCrawler) backtrack() {
+// backtrack undoes the latest words placed in the crossword. It returns false
+// if there is nothing left to undo, meaning that the layout can't be filled.
+func (c *crosswordCrawler) backtrack() bool {
+	if len(c.stack) == 0 {
+		return false
+	}
 	c.totalBacktracks++
 	if c.totalBacktracks%10 == 0 {
 		c.backtrackSteps += 3
@@ -186,4 +394,98 @@ func (c *crosswordCrawler) backtrack() {
 			break
 		}
 	}
+	return true
+}
+
+// blankRatio returns the proportion of blank squares of the playing area of
+// the crossword, the clue squares of an arrowword holding clues.
+func (c *Crossword) blankRatio() float64 {
+	blanks := 0
+	for pos, square := range c.data {
+		if square == Blank && !c.isMasked(pos) && (c.arrows == nil || c.arrows[pos] == 0) {
+			blanks++
+		}
+	}
+	return float64(blanks) / float64(c.squares())
+}
+
+// shapeWordLengths blanks out the words shorter than the minimum word length
+// of the rules and splits the words longer than the maximum one with a blank,
+// until every word fits. Blanking out a word can shorten the words crossing
+// it, so the words are checked again after every change.
+func shapeWordLengths(data []byte, rows, columns int, rules LayoutRules, random *rand.Rand) {
+	minLength := max(rules.MinWordLength, 2)
+	for changed := true; changed; {
+		changed = false
+		forEachRun(data, rows, columns, func(squares []int, across bool) {
+			switch {
+			case len(squares) < 2:
+			case len(squares) < minLength:
+				for _, pos := range squares {
+					data[pos] = Blank
+				}
+				changed = true
+			case rules.MaxWordLength > 0 && len(squares) > rules.MaxWordLength:
+				data[splitSquare(data, rows, columns, squares, across, minLength, random)] = Blank
+				changed = true
+			}
+		})
+	}
+}
+
+// splitSquare returns a random square of a run leaving at least minLength
+// squares on both sides, preferring the squares that no word crosses, or the
+// middle of the run if there is none.
+func splitSquare(data []byte, rows, columns int, squares []int, across bool, minLength int, random *rand.Rand) int {
+	if len(squares) <= 2*minLength {
+		return squares[len(squares)/2]
+	}
+	candidates, uncrossed := []int{}, []int{}
+	for _, pos := range squares[minLength : len(squares)-minLength] {
+		candidates = append(candidates, pos)
+		if !isCrossed(data, rows, columns, pos, across) {
+			uncrossed = append(uncrossed, pos)
+		}
+	}
+	if len(uncrossed) > 0 {
+		return uncrossed[random.Intn(len(uncrossed))]
+	}
+	return candidates[random.Intn(len(candidates))]
+}
+
+// isCrossed reports whether the square of an across (or down) run has a
+// letter above or below it (or left or right of it).
+func isCrossed(data []byte, rows, columns, pos int, across bool) bool {
+	row, column := pos/columns, pos%columns
+	if across {
+		return (row > 0 && data[pos-columns] != Blank) || (row < rows-1 && data[pos+columns] != Blank)
+	}
+	return (column > 0 && data[pos-1] != Blank) || (column < columns-1 && data[pos+1] != Blank)
+}
+
+// forEachRun calls f with the squares of every run of non-blank squares of
+// the rows, and then of the columns.
+func forEachRun(data []byte, rows, columns int, f func(squares []int, across bool)) {
+	run := []int{}
+	visit := func(pos int, last, across bool) {
+		if data[pos] != Blank {
+			run = append(run, pos)
+		}
+		if data[pos] == Blank || last {
+			if len(run) > 0 {
+				f(run, across)
+			}
+			run = run[:0]
+		}
+	}
+	for y := range rows {
+		for x := range columns {
+			visit(y*columns+x, x == columns-1, true)
+		}
+	}
+	for x := range columns {
+		for y := range rows {
+			visit(y*columns+x, y == rows-1, false)
+		}
+	}
 }
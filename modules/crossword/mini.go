@@ -0,0 +1,171 @@
+package crossword
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// MiniShaper lays out the dense grids of mini puzzles: every square is a
+// letter apart from Blocks blank squares (at most 4) placed in the corners of
+// the grid, diagonally opposite corners first.
+type MiniShaper struct {
+	Blocks int
+}
+
+func (s MiniShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := make([]bool, rows*columns)
+
+	corners := [][]int{
+		{0, rows*columns - 1},
+		{columns - 1, (rows - 1) * columns},
+	}
+	random.Shuffle(len(corners), func(i, j int) {
+		corners[i], corners[j] = corners[j], corners[i]
+	})
+	for i := range min(s.Blocks, 4) {
+		mask[corners[i/2][i%2]] = true
+	}
+
+	return mask
+}
+
+// miniSearch fills dense grids, where every letter is checked and the greedy
+// approach of generateCrossword rarely succeeds. It is a depth-first search
+// that always fills the word with the fewest candidates left, and rejects any
+// candidate leaving a crossing word without candidates.
+type miniSearch struct {
+	ctx           context.Context
+	words         []WordRef
+	crossings     [][]int
+	wordDict      dictionary.WordSource
+	random        *rand.Rand
+	backtracks    int
+	maxBacktracks int
+}
+
+// fillMini fills a dense crossword with a miniSearch. It returns nil if the
+// search is cancelled, runs out of backtracks (0 means no limit) or if the
+// crossword can't be filled.
+func fillMini(ctx context.Context, crossword *Crossword, wordDict dictionary.WordSource, random *rand.Rand, maxBacktracks int) *Crossword {
+	search := &miniSearch{
+		ctx:           ctx,
+		wordDict:      wordDict,
+		random:        random,
+		maxBacktracks: maxBacktracks,
+	}
+	for w := Word(crossword); w != nil; w = w.Next() {
+		search.words = append(search.words, *w)
+	}
+	search.crossings = wordCrossings(crossword, search.words)
+
+	if !search.fill() {
+		return nil
+	}
+	return crossword
+}
+
+func (s *miniSearch) fill() bool {
+	if s.ctx.Err() != nil || (s.maxBacktracks > 0 && s.backtracks >= s.maxBacktracks) {
+		return false
+	}
+
+	used := s.usedWords()
+	next, candidates := s.nextWord(used)
+	if next == -1 {
+		return true
+	}
+
+	word := &s.words[next]
+	previous := word.GetValue()
+	shuffleCandidates(s.wordDict, candidates, s.random)
+	for _, candidate := range candidates {
+		word.SetValue([]byte(s.wordDict.Word(candidate)))
+		if s.consistent(next) && s.fill() {
+			return true
+		}
+		word.SetValue(previous)
+		if s.ctx.Err() != nil {
+			return false
+		}
+	}
+
+	s.backtracks++
+	return false
+}
+
+// nextWord returns the unfilled word with the fewest candidates along with
+// its candidates, or -1 if the grid is filled. Words without any letter yet
+// are only considered when no other word is started, as they match every
+// word of their length.
+func (s *miniSearch) nextWord(used map[string]int) (int, []int) {
+	next, candidates := -1, []int(nil)
+	for i := range s.words {
+		value := s.words[i].GetValue()
+		if !slices.Contains(value, 0) || !slices.ContainsFunc(value, isSet) {
+			continue
+		}
+		wordCandidates := s.candidates(value, used)
+		if next == -1 || len(wordCandidates) < len(candidates) {
+			next, candidates = i, wordCandidates
+		}
+	}
+	if next != -1 {
+		return next, candidates
+	}
+	for i := range s.words {
+		if value := s.words[i].GetValue(); !s.words[i].IsFilled() {
+			return i, s.candidates(value, used)
+		}
+	}
+	return -1, nil
+}
+
+// candidates returns the indexes of the words of the dictionary that fit the
+// given value and aren't used anywhere else in the grid.
+func (s *miniSearch) candidates(value []byte, used map[string]int) []int {
+	return slices.DeleteFunc(s.wordDict.Candidates(value), func(index int) bool {
+		return used[s.wordDict.Word(index)] != 0
+	})
+}
+
+// consistent reports whether the words crossing the given word are either
+// distinct dictionary words or can still be completed into one.
+func (s *miniSearch) consistent(index int) bool {
+	used := s.usedWords()
+	if used[string(s.words[index].GetValue())] > 1 {
+		return false
+	}
+	for _, i := range s.crossings[index] {
+		value := s.words[i].GetValue()
+		if s.words[i].IsFilled() {
+			if used[string(value)] > 1 || !s.wordDict.Contains(string(value)) {
+				return false
+			}
+			continue
+		}
+		if !slices.ContainsFunc(s.wordDict.Candidates(value), func(index int) bool {
+			return used[s.wordDict.Word(index)] == 0
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// usedWords counts the filled words of the grid.
+func (s *miniSearch) usedWords() map[string]int {
+	used := map[string]int{}
+	for i := range s.words {
+		if s.words[i].IsFilled() {
+			used[string(s.words[i].GetValue())]++
+		}
+	}
+	return used
+}
+
+func isSet(letter byte) bool {
+	return letter != 0
+}
@@ -0,0 +1,206 @@
+package crossword
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Masked is the character used by ParseCrossword and String for the squares
+// outside the playing area of a grid.
+const Masked = '#'
+
+// Outline gives non-rectangular crosswords their shape. Outside returns a
+// mask of rows*columns squares, in row-major order, where true marks a square
+// outside the playing area. Masked squares are neither letters nor blank
+// squares: words never cross them and they are rendered as empty space.
+type Outline interface {
+	Outside(rows, columns int) []bool
+}
+
+var outlines = map[string]Outline{
+	"circle":  CircleOutline{},
+	"diamond": DiamondOutline{},
+	"heart":   HeartOutline{},
+	"star":    StarOutline{},
+}
+
+// OutlineByName returns the built-in outline registered under the given name.
+func OutlineByName(name string) (Outline, error) {
+	outline, exists := outlines[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown outline %q (available: %v)", name, OutlineNames())
+	}
+	return outline, nil
+}
+
+// OutlineNames returns the names of the built-in outlines in alphabetical
+// order.
+func OutlineNames() []string {
+	names := make([]string, 0, len(outlines))
+	for name := range outlines {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// outsideShape masks the squares whose center isn't inside a shape, given by
+// a function of coordinates running from -1 to 1 across the width and the
+// height of the grid, from the top left corner.
+func outsideShape(rows, columns int, inside func(x, y float64) bool) []bool {
+	mask := make([]bool, rows*columns)
+	for i := range rows {
+		for j := range columns {
+			x := (float64(j)+0.5)/float64(columns)*2 - 1
+			y := (float64(i)+0.5)/float64(rows)*2 - 1
+			mask[i*columns+j] = !inside(x, y)
+		}
+	}
+	return mask
+}
+
+// CircleOutline shapes the grid as the circle, or the ellipse, inscribed in
+// it.
+type CircleOutline struct{}
+
+func (CircleOutline) Outside(rows, columns int) []bool {
+	return outsideShape(rows, columns, func(x, y float64) bool {
+		return x*x+y*y <= 1
+	})
+}
+
+// DiamondOutline shapes the grid as the diamond joining the middles of its
+// edges.
+type DiamondOutline struct{}
+
+func (DiamondOutline) Outside(rows, columns int) []bool {
+	return outsideShape(rows, columns, func(x, y float64) bool {
+		return math.Abs(x)+math.Abs(y) <= 1
+	})
+}
+
+// HeartOutline shapes the grid as a heart, its point at the bottom.
+type HeartOutline struct{}
+
+func (HeartOutline) Outside(rows, columns int) []bool {
+	return outsideShape(rows, columns, func(x, y float64) bool {
+		// the curve (x²+y²-1)³ = x²y³ spans about [-1.14, 1.14] across and
+		// [-1, 1.24] up
+		x, y = 1.14*x, 0.12-1.12*y
+		a := x*x + y*y - 1
+		return a*a*a <= x*x*y*y*y
+	})
+}
+
+// starInnerRadius is the radius of the inner corners of StarOutline, relative
+// to the radius of its points. Stars thinner than this have points too narrow
+// for words.
+const starInnerRadius = 0.55
+
+// StarOutline shapes the grid as a five-pointed star, a point at the top.
+type StarOutline struct{}
+
+func (StarOutline) Outside(rows, columns int) []bool {
+	// the corners of the star alternate between its points and its inner
+	// corners, the star being lowered to center its height on the grid. Its
+	// points then span [-0.95, 0.95] across and [-0.9, 0.9] up.
+	corners := make([][2]float64, 10)
+	for k := range corners {
+		radius := 1.0
+		if k%2 == 1 {
+			radius = starInnerRadius
+		}
+		angle := math.Pi/2 + float64(k)*math.Pi/5
+		corners[k] = [2]float64{radius * math.Cos(angle), radius*math.Sin(angle) - 0.095}
+	}
+	return outsideShape(rows, columns, func(x, y float64) bool {
+		return insidePolygon(corners, 0.95*x, -0.9*y)
+	})
+}
+
+// insidePolygon reports whether the point (x, y) is inside the polygon of the
+// given corners, counting the edges crossed by a ray running right from it.
+func insidePolygon(corners [][2]float64, x, y float64) bool {
+	inside := false
+	for k := range corners {
+		a, b := corners[k], corners[(k+1)%len(corners)]
+		if (a[1] > y) != (b[1] > y) && x < a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// TemplateOutline shapes the grid after a template crossword (see
+// ParseOutline), whose masked squares are scaled to the size of the grid.
+type TemplateOutline struct {
+	template *Crossword
+}
+
+// ParseOutline reads the outline of a template written like a crossword for
+// ParseCrossword, using Masked for the squares outside the playing area.
+func ParseOutline(template string) (TemplateOutline, error) {
+	c, err := ParseCrossword(template)
+	if err != nil {
+		return TemplateOutline{}, err
+	}
+	if !c.HasMask() {
+		return TemplateOutline{}, fmt.Errorf("the template has no %q squares", Masked)
+	}
+	return TemplateOutline{template: c}, nil
+}
+
+// Size returns the number of rows and columns of the template.
+func (o TemplateOutline) Size() (int, int) {
+	return o.template.rows, o.template.columns
+}
+
+func (o TemplateOutline) Outside(rows, columns int) []bool {
+	mask := make([]bool, rows*columns)
+	for i := range rows {
+		for j := range columns {
+			row, column := i*o.template.rows/rows, j*o.template.columns/columns
+			mask[i*columns+j] = o.template.isMasked(row*o.template.columns + column)
+		}
+	}
+	return mask
+}
+
+// HasMask reports whether the crossword has squares outside its playing
+// area.
+func (c *Crossword) HasMask() bool {
+	return c.masked != nil
+}
+
+// AddMask masks a square outside the playing area, for non-rectangular
+// crosswords laid out by other means than NewCrossword.
+func (c *Crossword) AddMask(row, column int) {
+	if c.masked == nil {
+		c.masked = make([]bool, len(c.data))
+	}
+	c.masked[row*c.columns+column] = true
+	c.data[row*c.columns+column] = Blank
+}
+
+// isMasked reports whether the square at pos is outside the playing area.
+func (c *Crossword) isMasked(pos int) bool {
+	return c.masked != nil && c.masked[pos]
+}
+
+// squares returns the number of squares of the playing area of the crossword.
+func (c *Crossword) squares() int {
+	squares := len(c.data)
+	for _, masked := range c.masked {
+		if masked {
+			squares--
+		}
+	}
+	return squares
+}
+
+// IsMasked reports whether the square is outside the playing area of a
+// non-rectangular crossword. Masked squares are also blank.
+func (l *LetterRef) IsMasked() bool {
+	return l.crossword.isMasked(l.pos)
+}
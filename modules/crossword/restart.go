@@ -0,0 +1,50 @@
+package crossword
+
+import "math/rand"
+
+// restartUnit is the number of backtracks that make up one unit of the Luby
+// sequence used by restartPolicy.
+const restartUnit = 100
+
+// restartPolicy decides how many backtracks a generation attempt is allowed
+// before its layout is abandoned. The cutoffs follow the Luby sequence
+// (1, 1, 2, 1, 1, 2, 4, 1, 1, 2, ...) scaled by restartUnit, which keeps most
+// attempts short while still giving some layouts a long time to be filled.
+type restartPolicy struct {
+	attempt int
+}
+
+func newRestartPolicy() *restartPolicy {
+	return &restartPolicy{}
+}
+
+// next returns the backtrack cutoff of the next attempt.
+func (p *restartPolicy) next() int {
+	p.attempt++
+	return restartUnit * luby(p.attempt)
+}
+
+// luby returns the i-th term (starting at 1) of the Luby sequence.
+func luby(i int) int {
+	for {
+		// find k such that 2^(k-1) <= i < 2^k
+		k := 1
+		for 1<<k <= i {
+			k++
+		}
+		if i == 1<<k-1 {
+			return 1 << (k - 1)
+		}
+		i -= 1<<(k-1) - 1
+	}
+}
+
+// newSeed derives a new non-zero generation seed, since a zero seed asks
+// NewCrossword for a random crossword.
+func newSeed(random *rand.Rand) int64 {
+	for {
+		if seed := random.Int63(); seed != 0 {
+			return seed
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package crossword
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Shaper decides where the blank squares of an empty crossword go. Shape
+// returns a mask of rows*columns cells, in row-major order, where true marks a
+// blank square. Single letter words left over by the mask are blanked out by
+// the generator afterwards.
+type Shaper interface {
+	Shape(rows, columns int, random *rand.Rand) []bool
+}
+
+var shapers = map[string]Shaper{
+	"barred":    BarredShaper{},
+	"classic":   ClassicShaper{},
+	"open":      OpenShaper{},
+	"corners":   CornersShaper{},
+	"staircase": StaircaseShaper{},
+}
+
+// ShaperByName returns the built-in shaper registered under the given name.
+func ShaperByName(name string) (Shaper, error) {
+	shaper, exists := shapers[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown shape %q (available: %v)", name, ShaperNames())
+	}
+	return shaper, nil
+}
+
+// ShaperNames returns the names of the built-in shapers in alphabetical order.
+func ShaperNames() []string {
+	names := make([]string, 0, len(shapers))
+	for name := range shapers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// classicSpan is the number of squares of a row or column that ClassicShaper
+// scatters one random blank over. Larger grids get more random blanks, so that
+// their words stay short enough to be filled.
+const classicSpan = 7
+
+// ClassicShaper is the default layout: a checkerboard of blank squares on odd
+// rows, plus random blanks scattered over columns and even rows.
+type ClassicShaper struct{}
+
+func (ClassicShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := make([]bool, rows*columns)
+
+	for i := range rows {
+		for j := range columns {
+			if i%2 == 1 && (i+j)%2 == 0 {
+				mask[i*columns+j] = true
+			}
+			if i == 0 && j%2 == 0 {
+				for range max(1, rows/classicSpan) {
+					if random.Float64() < 0.75 {
+						mask[j+random.Intn(rows)*columns] = true
+					}
+				}
+			}
+		}
+
+		if i%2 == 0 && columns > 7 {
+			for range max(1, columns/classicSpan) {
+				if random.Float64() < 0.75 {
+					mask[i*columns+random.Intn(columns)] = true
+				}
+			}
+		}
+	}
+
+	return mask
+}
+
+// openMaxRun is the longest word OpenShaper allows on large grids.
+const openMaxRun = 10
+
+// openMaxLength returns the longest word OpenShaper allows on a grid of the
+// given size, a couple of letters shorter than the grid.
+func openMaxLength(rows, columns int) int {
+	return min(max(5, max(rows, columns)-2), openMaxRun)
+}
+
+// OpenShaper keeps the checkerboard of odd rows and only adds the blank
+// squares needed to keep words a couple of letters shorter than the grid,
+// producing grids with as few blank squares as the generator can reliably fill.
+type OpenShaper struct{}
+
+func (OpenShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := checkerboardMask(rows, columns)
+	splitLongRuns(mask, rows, columns, openMaxLength(rows, columns), random)
+	return mask
+}
+
+// CornersShaper produces the classic layout with the four corners of the grid
+// blocked off by triangles of blank squares.
+type CornersShaper struct{}
+
+func (CornersShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := ClassicShaper{}.Shape(rows, columns, random)
+
+	size := min(rows, columns) / 4
+	for i := range rows {
+		for j := range columns {
+			top, left := i, j
+			bottom, right := rows-1-i, columns-1-j
+			if min(top, bottom)+min(left, right) < size {
+				mask[i*columns+j] = true
+			}
+		}
+	}
+
+	return mask
+}
+
+// staircaseMaxRun is the longest down word StaircaseShaper allows.
+const staircaseMaxRun = 7
+
+// StaircaseShaper keeps the checkerboard of odd rows and lays the blank squares
+// of even rows out as diagonal steps running from the top left to the bottom
+// right of the grid. Steps only break across words, so long columns are then
+// split at random.
+type StaircaseShaper struct{}
+
+func (StaircaseShaper) Shape(rows, columns int, random *rand.Rand) []bool {
+	mask := checkerboardMask(rows, columns)
+
+	// blanks are kept on odd columns, whose squares on odd rows are blank
+	// already, so that steps never cut down words
+	period := 6 + 2*random.Intn(2)
+	offset := 1 + 2*random.Intn(period/2)
+	for i := 0; i < rows; i += 2 {
+		for j := range columns {
+			if (j-offset-i)%period == 0 && columns > 5 {
+				mask[i*columns+j] = true
+			}
+		}
+	}
+
+	splitLongRuns(mask, rows, columns, staircaseMaxRun, random)
+	return mask
+}
+
+// checkerboardMask blanks every other square of odd rows, which is the
+// structure all built-in shapers are built upon.
+func checkerboardMask(rows, columns int) []bool {
+	mask := make([]bool, rows*columns)
+	for i := 1; i < rows; i += 2 {
+		for j := range columns {
+			if (i+j)%2 == 0 {
+				mask[i*columns+j] = true
+			}
+		}
+	}
+	return mask
+}
+
+// splitLongRuns adds blank squares to break rows and columns of letters longer
+// than maxRun at random positions, avoiding squares that would cut the grid in
+// two whenever possible.
+func splitLongRuns(mask []bool, rows, columns, maxRun int, random *rand.Rand) {
+	// first rows, then columns
+	for _, horizontal := range []bool{true, false} {
+		lines, length := rows, columns
+		if !horizontal {
+			lines, length = columns, rows
+		}
+		index := func(line, k int) int {
+			if horizontal {
+				return line*columns + k
+			}
+			return k*columns + line
+		}
+		// crossed reports whether a square also belongs to a word running
+		// across the line
+		crossed := func(line, k int) bool {
+			return (line > 0 && !mask[index(line-1, k)]) ||
+				(line < lines-1 && !mask[index(line+1, k)])
+		}
+
+		for line := range lines {
+			start := 0
+			for start < length {
+				if mask[index(line, start)] {
+					start++
+					continue
+				}
+				end := start
+				for end < length && !mask[index(line, end)] {
+					end++
+				}
+				if run := end - start; run > maxRun {
+					// blank a square leaving at most maxRun letters on its left
+					// and, when that leaves enough choice, on its right too.
+					// otherwise what remains on the right is split again.
+					low, high := max(1, run-1-maxRun), min(maxRun, run-2)
+					if high-low < 2 {
+						low = min(2, high)
+					}
+					// try the candidate squares in random order, starting with
+					// those that aren't crossed, so that only one word is cut
+					splits := []int{}
+					for k := start + low; k <= start+high; k++ {
+						splits = append(splits, k)
+					}
+					random.Shuffle(len(splits), func(i, j int) {
+						splits[i], splits[j] = splits[j], splits[i]
+					})
+					slices.SortStableFunc(splits, func(a, b int) int {
+						return btoi(crossed(line, a)) - btoi(crossed(line, b))
+					})
+					split := splits[0]
+					for _, k := range splits {
+						mask[index(line, k)] = true
+						connected := isMaskConnected(mask, rows, columns)
+						mask[index(line, k)] = false
+						if connected {
+							split = k
+							break
+						}
+					}
+					mask[index(line, split)] = true
+					end = split
+				}
+				start = end
+			}
+		}
+	}
+}
+
+// isMaskConnected reports whether the letters of a mask form a single region,
+// ignoring lone letters which the generator blanks out anyway.
+func isMaskConnected(mask []bool, rows, columns int) bool {
+	_, sizes := regions(rows, columns, func(pos int) bool {
+		return !mask[pos]
+	})
+	found := false
+	for _, size := range sizes {
+		if size > 1 {
+			if found {
+				return false
+			}
+			found = true
+		}
+	}
+	return true
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package crossword
+
+import "slices"
+
+// scrabbleScores are the Scrabble values of the letters, from a to z.
+var scrabbleScores = [26]int{1, 3, 3, 2, 1, 4, 2, 4, 1, 8, 5, 1, 3, 1, 1, 3, 10, 1, 1, 1, 1, 4, 4, 8, 4, 10}
+
+// Statistics describe the layout and the fill of a crossword.
+type Statistics struct {
+	Rows    int `json:"rows"`
+	Columns int `json:"columns"`
+	// Words counts the across and down words, of at least two letters.
+	Words  int `json:"words"`
+	Across int `json:"across"`
+	Down   int `json:"down"`
+	// Lengths counts the words of every length.
+	Lengths       map[int]int `json:"lengths"`
+	AverageLength float64     `json:"averageLength"`
+	// Longest lists the longest words, in the order of Word and without
+	// duplicates.
+	Longest []string `json:"longest"`
+	// Blanks counts the blank squares of the playing area, BlankRatio being
+	// their proportion of it, and Masked the squares outside of it.
+	Blanks     int     `json:"blanks"`
+	BlankRatio float64 `json:"blankRatio"`
+	Masked     int     `json:"masked,omitempty"`
+	// Checked counts the letters belonging to both an across and a down word,
+	// and Unchecked the other letters.
+	Checked   int `json:"checked"`
+	Unchecked int `json:"unchecked"`
+	// Letters counts the occurrences of every letter of the grid.
+	Letters map[string]int `json:"letters"`
+	// ScrabbleScore sums the Scrabble values of the letters of the grid, each
+	// square counting once.
+	ScrabbleScore int `json:"scrabbleScore"`
+}
+
+// Stats computes the statistics of a crossword. Empty squares are counted as
+// unchecked letters, without adding to the letter counts.
+func Stats(c *Crossword) Statistics {
+	s := Statistics{
+		Rows:    c.rows,
+		Columns: c.columns,
+		Lengths: map[int]int{},
+		Longest: []string{},
+		Letters: map[string]int{},
+	}
+
+	letters := 0
+	for word := Word(c); word != nil; word = word.Next() {
+		s.Words++
+		if word.direction == horizontal {
+			s.Across++
+		} else {
+			s.Down++
+		}
+		s.Lengths[word.length]++
+		letters += word.length
+		if len(s.Longest) > 0 && word.length > len(s.Longest[0]) {
+			s.Longest = s.Longest[:0]
+		}
+		if value := string(word.GetValue()); (len(s.Longest) == 0 || word.length == len(s.Longest[0])) && !slices.Contains(s.Longest, value) {
+			s.Longest = append(s.Longest, value)
+		}
+	}
+	if s.Words > 0 {
+		s.AverageLength = float64(letters) / float64(s.Words)
+	}
+
+	unchecked := c.uncheckedCells()
+	for pos, value := range c.data {
+		switch {
+		case c.isMasked(pos):
+			s.Masked++
+		case value == Blank:
+			s.Blanks++
+		case value == 0:
+			// empty squares have no letter to count
+		default:
+			s.Letters[string(value)]++
+			if value >= 'a' && value <= 'z' {
+				s.ScrabbleScore += scrabbleScores[value-'a']
+			}
+		}
+	}
+	s.BlankRatio = float64(s.Blanks) / float64(c.squares())
+	s.Unchecked = len(unchecked)
+	s.Checked = c.squares() - s.Blanks - s.Unchecked
+	return s
+}
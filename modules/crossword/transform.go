@@ -0,0 +1,142 @@
+package crossword
+
+import "fmt"
+
+// Transpose returns the crossword mirrored along its main diagonal, its rows
+// becoming its columns, so that across words become down words and the other
+// way around.
+func (c *Crossword) Transpose() *Crossword {
+	return c.remap(c.columns, c.rows, func(row, column int) (int, int, bool) {
+		return column, row, true
+	})
+}
+
+// Rotate returns the crossword rotated clockwise by the given number of
+// quarter turns: 1 for 90°, 2 for 180° and 3 for 270°. Negative numbers rotate
+// it counterclockwise.
+func (c *Crossword) Rotate(quarterTurns int) *Crossword {
+	switch (quarterTurns%4 + 4) % 4 {
+	case 1:
+		return c.remap(c.columns, c.rows, func(row, column int) (int, int, bool) {
+			return c.rows - 1 - column, row, true
+		})
+	case 2:
+		return c.remap(c.rows, c.columns, func(row, column int) (int, int, bool) {
+			return c.rows - 1 - row, c.columns - 1 - column, true
+		})
+	case 3:
+		return c.remap(c.columns, c.rows, func(row, column int) (int, int, bool) {
+			return column, c.columns - 1 - row, true
+		})
+	}
+	return c.Clone()
+}
+
+// MirrorHorizontally returns the crossword mirrored from left to right, its
+// across words being read backwards.
+func (c *Crossword) MirrorHorizontally() *Crossword {
+	return c.remap(c.rows, c.columns, func(row, column int) (int, int, bool) {
+		return row, c.columns - 1 - column, true
+	})
+}
+
+// MirrorVertically returns the crossword mirrored from top to bottom, its down
+// words being read backwards.
+func (c *Crossword) MirrorVertically() *Crossword {
+	return c.remap(c.rows, c.columns, func(row, column int) (int, int, bool) {
+		return c.rows - 1 - row, column, true
+	})
+}
+
+// Crop returns the rows*columns squares of the crossword starting at the given
+// row and column, which can be negative. Squares beyond the edges of the
+// crossword pad the grid: they are masked if the crossword has a mask and
+// blank otherwise, so that they never extend its words. It panics if rows or
+// columns is less than 1.
+func (c *Crossword) Crop(row, column, rows, columns int) *Crossword {
+	if rows < 1 {
+		panic(fmt.Sprintf("invalid rows: %d", rows))
+	}
+	if columns < 1 {
+		panic(fmt.Sprintf("invalid columns: %d", columns))
+	}
+	return c.remap(rows, columns, func(r, col int) (int, int, bool) {
+		r, col = r+row, col+column
+		return r, col, r >= 0 && r < c.rows && col >= 0 && col < c.columns
+	})
+}
+
+// remap returns a crossword of the given size whose squares are taken from
+// the squares of c given by source, along with their mask and the bars
+// between them. Squares without a source are padding. Clue squares are marked
+// again for the new words of an arrowword.
+func (c *Crossword) remap(rows, columns int, source func(row, column int) (int, int, bool)) *Crossword {
+	result := &Crossword{
+		rows:    rows,
+		columns: columns,
+		data:    make([]byte, rows*columns),
+	}
+	if c.masked != nil {
+		result.masked = make([]bool, rows*columns)
+	}
+	if c.bars != nil {
+		result.bars = make([]Bar, rows*columns)
+	}
+
+	// sourcePos returns the position in c of the square of the result at
+	// (row, column), -1 for padding
+	sourcePos := func(row, column int) int {
+		if row >= rows || column >= columns {
+			return -1
+		}
+		sourceRow, sourceColumn, ok := source(row, column)
+		if !ok {
+			return -1
+		}
+		return sourceRow*c.columns + sourceColumn
+	}
+	for row := range rows {
+		for column := range columns {
+			pos, from := row*columns+column, sourcePos(row, column)
+			switch {
+			case from == -1 && c.masked != nil:
+				result.masked[pos] = true
+				result.data[pos] = Blank
+			case from == -1:
+				result.data[pos] = Blank
+			default:
+				result.data[pos] = c.data[from]
+				if c.masked != nil {
+					result.masked[pos] = c.masked[from]
+				}
+			}
+			if c.bars == nil || from == -1 {
+				continue
+			}
+			if right := sourcePos(row, column+1); right != -1 && c.hasEdgeBar(from, right) {
+				result.bars[pos] |= BarRight
+			}
+			if below := sourcePos(row+1, column); below != -1 && c.hasEdgeBar(from, below) {
+				result.bars[pos] |= BarBottom
+			}
+		}
+	}
+
+	if c.arrows != nil {
+		result.MarkClueSquares()
+	}
+	return result
+}
+
+// hasEdgeBar reports whether there is a bar between the adjacent squares at
+// a and b.
+func (c *Crossword) hasEdgeBar(a, b int) bool {
+	a, b = min(a, b), max(a, b)
+	switch {
+	case b == a+1 && a/c.columns == b/c.columns:
+		return c.hasBar(a, BarRight)
+	case b == a+c.columns:
+		return c.hasBar(a, BarBottom)
+	}
+	return false
+}
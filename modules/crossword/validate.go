@@ -0,0 +1,293 @@
+package crossword
+
+import "fmt"
+
+// LayoutRules are the construction rules a crossword layout must follow.
+type LayoutRules struct {
+	// LimitUnchecked enables the check of MaxUncheckedRatio, the highest
+	// proportion of letters allowed to belong to a single word. A ratio of 0
+	// requires every letter to belong to both an across and a down word.
+	LimitUnchecked    bool
+	MaxUncheckedRatio float64
+	// MinWordLength and MaxWordLength bound the length of the words, zero
+	// meaning no bound.
+	MinWordLength int
+	MaxWordLength int
+	// MinDensity and MaxDensity bound the proportion of blank squares of the
+	// grid, zero meaning no bound.
+	MinDensity float64
+	MaxDensity float64
+}
+
+// boundsWordLength reports whether the rules bound the length of the words
+// beyond the two letters of any word.
+func (rules LayoutRules) boundsWordLength() bool {
+	return rules.MinWordLength > 2 || rules.MaxWordLength > 0
+}
+
+type ViolationKind int
+
+const (
+	// DisconnectedRegion reports a region of letters that can't be reached
+	// from the rest of the grid.
+	DisconnectedRegion ViolationKind = iota
+	// TooManyUncheckedCells reports a grid where too many letters belong to a
+	// single word.
+	TooManyUncheckedCells
+	// UnanchoredWord reports a word of an arrowword that no clue square points
+	// to.
+	UnanchoredWord
+	// ShortWord reports a word shorter than the minimum word length.
+	ShortWord
+	// LongWord reports a word longer than the maximum word length.
+	LongWord
+	// BlankDensity reports a grid whose proportion of blank squares is out of
+	// the density range.
+	BlankDensity
+	// TooFewLetters reports a grid without any across or down word.
+	TooFewLetters
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case DisconnectedRegion:
+		return "disconnected region"
+	case TooManyUncheckedCells:
+		return "too many unchecked cells"
+	case UnanchoredWord:
+		return "unanchored word"
+	case ShortWord:
+		return "short word"
+	case LongWord:
+		return "long word"
+	case BlankDensity:
+		return "blank density"
+	case TooFewLetters:
+		return "too few letters"
+	}
+	return fmt.Sprintf("ViolationKind(%d)", int(k))
+}
+
+// Violation is a broken layout rule. Row and Column locate the first cell
+// involved in the violation.
+type Violation struct {
+	Kind    ViolationKind
+	Row     int
+	Column  int
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("(Row: %d, Col: %d) %s: %s", v.Row+1, v.Column+1, v.Kind, v.Message)
+}
+
+// Validate checks the layout of the crossword against the given rules and
+// returns the violations found, if any. All white squares must form a single
+// connected region holding words across and down, and every word of an
+// arrowword must be pointed to by a clue square.
+func (c *Crossword) Validate(rules LayoutRules) []Violation {
+	violations := c.connectivityViolations()
+	violations = append(violations, c.letterViolations()...)
+	violations = append(violations, c.uncheckedViolations(rules)...)
+	violations = append(violations, c.anchorViolations()...)
+	violations = append(violations, c.lengthViolations(rules.MinWordLength, rules.MaxWordLength)...)
+	violations = append(violations, c.densityViolations(rules)...)
+	return violations
+}
+
+// lengthViolations reports the words shorter than minLength or longer than
+// maxLength, when they are set.
+func (c *Crossword) lengthViolations(minLength, maxLength int) []Violation {
+	violations := []Violation{}
+	if minLength == 0 && maxLength == 0 {
+		return violations
+	}
+	for w := Word(c); w != nil; w = w.Next() {
+		switch {
+		case w.length < minLength:
+			violations = append(violations, Violation{
+				Kind:    ShortWord,
+				Row:     w.pos / c.columns,
+				Column:  w.pos % c.columns,
+				Message: fmt.Sprintf("the %s word has %d letters (min %d)", w.direction, w.length, minLength),
+			})
+		case maxLength > 0 && w.length > maxLength:
+			violations = append(violations, Violation{
+				Kind:    LongWord,
+				Row:     w.pos / c.columns,
+				Column:  w.pos % c.columns,
+				Message: fmt.Sprintf("the %s word has %d letters (max %d)", w.direction, w.length, maxLength),
+			})
+		}
+	}
+	return violations
+}
+
+// anchorViolations reports the words of an arrowword starting on the edge of
+// the grid, where there is no square for their clue.
+func (c *Crossword) anchorViolations() []Violation {
+	violations := []Violation{}
+	if !c.IsArrowword() {
+		return violations
+	}
+	for w := Word(c); w != nil; w = w.Next() {
+		if (w.direction == horizontal && w.pos%c.columns != 0) || (w.direction == vertical && w.pos >= c.columns) {
+			continue
+		}
+		violations = append(violations, Violation{
+			Kind:    UnanchoredWord,
+			Row:     w.pos / c.columns,
+			Column:  w.pos % c.columns,
+			Message: fmt.Sprintf("the %s word of %d letters has no clue square", w.direction, w.length),
+		})
+	}
+	return violations
+}
+
+// connectivityViolations reports every region of letters but the largest one.
+func (c *Crossword) connectivityViolations() []Violation {
+	region, sizes := regions(c.rows, c.columns, func(pos int) bool {
+		return c.data[pos] != Blank
+	})
+
+	largest := 0
+	for id := range sizes {
+		if sizes[id] > sizes[largest] {
+			largest = id
+		}
+	}
+
+	violations := []Violation{}
+	reported := make([]bool, len(sizes))
+	for pos, id := range region {
+		if id == 0 || id == largest || reported[id] {
+			continue
+		}
+		reported[id] = true
+		violations = append(violations, Violation{
+			Kind:    DisconnectedRegion,
+			Row:     pos / c.columns,
+			Column:  pos % c.columns,
+			Message: fmt.Sprintf("%d letters are disconnected from the rest of the grid", sizes[id]),
+		})
+	}
+	return violations
+}
+
+// letterViolations reports a grid without any across or down word, such as a
+// small outline left with a single word.
+func (c *Crossword) letterViolations() []Violation {
+	across, down := 0, 0
+	for w := Word(c); w != nil; w = w.Next() {
+		if w.direction == horizontal {
+			across++
+		} else {
+			down++
+		}
+	}
+	if across > 0 && down > 0 {
+		return []Violation{}
+	}
+	return []Violation{{
+		Kind:    TooFewLetters,
+		Message: fmt.Sprintf("the grid has %d across and %d down words", across, down),
+	}}
+}
+
+// regions labels the connected regions of a rows*columns grid formed by the
+// squares for which isLetter returns true. It returns the region of every
+// square, 0 for squares that aren't letters and ids starting at 1 otherwise,
+// along with the size of every region indexed by id.
+func regions(rows, columns int, isLetter func(pos int) bool) ([]int, []int) {
+	region := make([]int, rows*columns)
+	sizes := []int{0}
+
+	for pos := range region {
+		if !isLetter(pos) || region[pos] != 0 {
+			continue
+		}
+		id := len(sizes)
+		sizes = append(sizes, 0)
+
+		queue := []int{pos}
+		region[pos] = id
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			sizes[id]++
+			for _, next := range neighbours(rows, columns, current) {
+				if isLetter(next) && region[next] == 0 {
+					region[next] = id
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return region, sizes
+}
+
+// uncheckedViolations reports a grid whose proportion of unchecked letters is
+// above the maximum ratio of the rules, when it is limited.
+func (c *Crossword) uncheckedViolations(rules LayoutRules) []Violation {
+	if !rules.LimitUnchecked {
+		return []Violation{}
+	}
+
+	unchecked := c.uncheckedCells()
+	letters := 0
+	for _, value := range c.data {
+		if value != Blank {
+			letters++
+		}
+	}
+	if len(unchecked) == 0 || float64(len(unchecked)) <= rules.MaxUncheckedRatio*float64(letters) {
+		return []Violation{}
+	}
+
+	return []Violation{{
+		Kind:    TooManyUncheckedCells,
+		Row:     unchecked[0] / c.columns,
+		Column:  unchecked[0] % c.columns,
+		Message: fmt.Sprintf("%d of %d letters are unchecked (max %.0f%%)", len(unchecked), letters, rules.MaxUncheckedRatio*100),
+	}}
+}
+
+// uncheckedCells returns the positions of the letters that don't belong to
+// both an across and a down word.
+func (c *Crossword) uncheckedCells() []int {
+	words := make([]int, len(c.data))
+	for word := Word(c); word != nil; word = word.Next() {
+		for letter := WordLetter(word); letter != nil; letter = letter.Next() {
+			words[letter.pos]++
+		}
+	}
+
+	unchecked := []int{}
+	for pos, value := range c.data {
+		if value != Blank && words[pos] < 2 {
+			unchecked = append(unchecked, pos)
+		}
+	}
+	return unchecked
+}
+
+// neighbours returns the positions of the squares directly above, below, left
+// and right of pos in a rows*columns grid.
+func neighbours(rows, columns, pos int) []int {
+	row, column := pos/columns, pos%columns
+	neighbours := make([]int, 0, 4)
+	if row > 0 {
+		neighbours = append(neighbours, pos-columns)
+	}
+	if row < rows-1 {
+		neighbours = append(neighbours, pos+columns)
+	}
+	if column > 0 {
+		neighbours = append(neighbours, pos-1)
+	}
+	if column < columns-1 {
+		neighbours = append(neighbours, pos+1)
+	}
+	return neighbours
+}
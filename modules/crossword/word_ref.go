@@ -20,7 +20,13 @@ const (
 )
 
 func Word(c *Crossword) *WordRef {
-	return rowWord(0, c).WordRef
+	if rowWord := rowWord(0, c); rowWord != nil {
+		return rowWord.WordRef
+	}
+	if columnWord := columnWord(0, c); columnWord != nil {
+		return columnWord.WordRef
+	}
+	return nil
 }
 
 func (w *WordRef) Next() *WordRef {
@@ -109,7 +115,7 @@ func rowWord(pos int, c *Crossword) *RowWordRef {
 				wordStart = i
 			}
 			wordLength++
-			if (i+1)%c.columns == 0 || c.data[i+1] == '.' {
+			if (i+1)%c.columns == 0 || c.data[i+1] == '.' || c.hasBar(i, BarRight) {
 				if wordLength > 1 {
 					return &RowWordRef{
 						&WordRef{
@@ -159,7 +165,7 @@ func columnWord(pos int, c *Crossword) *ColumnWordRef {
 				wordStart = i
 			}
 			wordLength++
-			if i+c.columns >= len(c.data) || c.data[i+c.columns] == '.' {
+			if i+c.columns >= len(c.data) || c.data[i+c.columns] == '.' || c.hasBar(i, BarBottom) {
 				if wordLength > 1 {
 					return &ColumnWordRef{
 						&WordRef{
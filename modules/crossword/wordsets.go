@@ -0,0 +1,155 @@
+package crossword
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// maxPlacementTries bounds the number of slots tried by placeRequiredWords in
+// a layout before giving up on it.
+const maxPlacementTries = 100
+
+// penalizedWeight is the weight of the penalized words of a configuration,
+// the other words weighing 1.
+const penalizedWeight = 0.05
+
+// withWordSets checks the excluded and required words of the configuration
+// and returns it with a WordDict filling crosswords with the words of WordDict
+// that aren't excluded, the penalized ones being picked less often, plus the
+// required words.
+func (config CrosswordConfig) withWordSets() (CrosswordConfig, error) {
+	if len(config.Exclude) == 0 && len(config.Require) == 0 && len(config.Penalize) == 0 {
+		return config, nil
+	}
+
+	excluded := wordSet(config.Exclude)
+	penalized := wordSet(config.Penalize)
+	require, err := normalizeWords(config.Require)
+	if err != nil {
+		return config, err
+	}
+	for _, word := range require {
+		if excluded[word] {
+			return config, fmt.Errorf("word %q is both required and excluded", word)
+		}
+		if len(word) > max(config.Rows, config.Cols) {
+			return config, fmt.Errorf("word %q doesn't fit in a %dx%d grid", word, config.Rows, config.Cols)
+		}
+	}
+
+	wordDict := config.WordDict
+	if len(excluded) > 0 {
+		wordDict = dictionary.Filtered(wordDict, func(word string) bool {
+			return !excluded[word]
+		})
+	}
+	if len(penalized) > 0 {
+		wordDict = dictionary.Weighted(wordDict, func(word string) float64 {
+			if penalized[word] {
+				return penalizedWeight
+			}
+			return 1
+		})
+	}
+	if len(require) > 0 {
+		wordDict = dictionary.Merged(wordDict, dictionary.NewWordDictionaryFromWords(require))
+	}
+	config.WordDict = wordDict
+	config.Require = require
+	return config, nil
+}
+
+// wordSet returns the set of the given words, trimmed and in lower case.
+func wordSet(words []string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range words {
+		set[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return set
+}
+
+// placeRequiredWords writes the words, longest first, in random slots of the
+// crossword where they agree with the words already placed and leave every
+// crossing word fillable. It returns false if no such placement was found
+// within maxPlacementTries slots.
+func placeRequiredWords(c *Crossword, words []string, wordDict dictionary.WordSource, random *rand.Rand) bool {
+	if len(words) == 0 {
+		return true
+	}
+	words = slices.Clone(words)
+	slices.SortStableFunc(words, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	slots := []WordRef{}
+	free := map[int]int{}
+	for w := Word(c); w != nil; w = w.Next() {
+		slots = append(slots, *w)
+		free[w.length]++
+	}
+	for _, word := range words {
+		if free[len(word)]--; free[len(word)] < 0 {
+			return false
+		}
+	}
+	crossings := wordCrossings(c, slots)
+	used := make([]bool, len(slots))
+	tries := 0
+
+	var place func(i int) bool
+	place = func(i int) bool {
+		if i == len(words) {
+			return true
+		}
+		for _, slot := range random.Perm(len(slots)) {
+			if used[slot] || slots[slot].length != len(words[i]) || !fits(slots[slot].GetValue(), words[i]) {
+				continue
+			}
+			if tries++; tries > maxPlacementTries {
+				return false
+			}
+			previous := slots[slot].GetValue()
+			slots[slot].SetValue([]byte(words[i]))
+			used[slot] = true
+			if crossingsFillable(slots, crossings[slot], wordDict) && place(i+1) {
+				return true
+			}
+			slots[slot].SetValue(previous)
+			used[slot] = false
+		}
+		return false
+	}
+	return place(0)
+}
+
+// fits reports whether the word agrees with the letters already in a slot.
+func fits(value []byte, word string) bool {
+	for i, letter := range value {
+		if letter != 0 && letter != word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// crossingsFillable reports whether the given words are either valid words or
+// can still be completed into one.
+func crossingsFillable(words []WordRef, indexes []int, wordDict dictionary.WordSource) bool {
+	for _, i := range indexes {
+		value := words[i].GetValue()
+		if words[i].IsFilled() {
+			if !wordDict.Contains(string(value)) {
+				return false
+			}
+			continue
+		}
+		if len(wordDict.Candidates(value)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,291 @@
+package dictionary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DAWG is a word source storing its words in a directed acyclic word graph: a
+// trie whose identical suffix subtrees are merged. It takes much less memory
+// than a WordDictionary, and patterns starting with known letters only visit
+// the words sharing them. Words are indexed in alphabetical order.
+type DAWG struct {
+	nodes []dawgNode
+	edges []dawgEdge
+}
+
+type dawgNode struct {
+	// lengths has bit i set if a word ends i letters below the node, bit 63
+	// standing for 63 letters or more.
+	lengths uint64
+	// count is the number of words ending at or below the node.
+	count     int32
+	firstEdge int32
+	edgeCount uint16
+	final     bool
+}
+
+type dawgEdge struct {
+	target int32
+	letter byte
+}
+
+// maxLengthBit is the bit of dawgNode.lengths standing for the longest words.
+const maxLengthBit = 63
+
+// NewDAWG returns a DAWG of the embedded word list.
+func NewDAWG() DAWG {
+	return NewDAWGFromWords(strings.Fields(words))
+}
+
+// NewDAWGFromWords returns a DAWG of the given words, which can't contain '.'
+// or 0, like the words of NewWordDictionaryFromWords.
+func NewDAWGFromWords(words []string) DAWG {
+	words = slices.Clone(words)
+	slices.Sort(words)
+	words = slices.Compact(words)
+
+	builder := newDAWGBuilder()
+	for _, word := range words {
+		builder.insert(word)
+	}
+	return builder.build()
+}
+
+func (d DAWG) Contains(word string) bool {
+	node, ok := d.walk(word)
+	return ok && d.nodes[node].final
+}
+
+// HasPrefix reports whether a word of the DAWG starts with the prefix.
+func (d DAWG) HasPrefix(prefix string) bool {
+	_, ok := d.walk(prefix)
+	return ok
+}
+
+func (d DAWG) Candidates(pattern []byte) []int {
+	candidates := []int{}
+	if len(d.nodes) > 0 {
+		d.match(0, 0, pattern, &candidates)
+	}
+	return candidates
+}
+
+// Word returns the word of an index, panicking if the index is out of range
+// like the other sources do.
+func (d DAWG) Word(index int) string {
+	if index < 0 || index >= d.Len() {
+		panic(fmt.Sprintf("dictionary: word index %d out of range [0:%d]", index, d.Len()))
+	}
+	word := []byte{}
+	node := int32(0)
+	for {
+		n := d.nodes[node]
+		if n.final {
+			if index == 0 {
+				return string(word)
+			}
+			index--
+		}
+		for _, edge := range d.edges[n.firstEdge : n.firstEdge+int32(n.edgeCount)] {
+			count := int(d.nodes[edge.target].count)
+			if index < count {
+				word = append(word, edge.letter)
+				node = edge.target
+				break
+			}
+			index -= count
+		}
+	}
+}
+
+// Len returns the number of words of the DAWG.
+func (d DAWG) Len() int {
+	if len(d.nodes) == 0 {
+		return 0
+	}
+	return int(d.nodes[0].count)
+}
+
+// walk follows the letters of the prefix from the root, reporting false if
+// no word starts with it.
+func (d DAWG) walk(prefix string) (int32, bool) {
+	if len(d.nodes) == 0 {
+		return 0, false
+	}
+	node := int32(0)
+	for i := range len(prefix) {
+		next, ok := d.child(node, prefix[i])
+		if !ok {
+			return 0, false
+		}
+		node = next
+	}
+	return node, true
+}
+
+func (d DAWG) child(node int32, letter byte) (int32, bool) {
+	n := &d.nodes[node]
+	for _, edge := range d.edges[n.firstEdge : n.firstEdge+int32(n.edgeCount)] {
+		if edge.letter >= letter {
+			return edge.target, edge.letter == letter
+		}
+	}
+	return 0, false
+}
+
+// match appends the indexes of the words below the node matching the rest of
+// the pattern, rank being the index of the first word below the node.
+func (d DAWG) match(node int32, rank int, pattern []byte, candidates *[]int) {
+	n := &d.nodes[node]
+	if len(pattern) == 0 {
+		if n.final {
+			*candidates = append(*candidates, rank)
+		}
+		return
+	}
+	if n.lengths&(1<<min(len(pattern), maxLengthBit)) == 0 {
+		return
+	}
+	if n.final {
+		rank++
+	}
+	for _, edge := range d.edges[n.firstEdge : n.firstEdge+int32(n.edgeCount)] {
+		if pattern[0] == 0 || pattern[0] == edge.letter {
+			d.match(edge.target, rank, pattern[1:], candidates)
+		}
+		rank += int(d.nodes[edge.target].count)
+	}
+}
+
+// dawgBuilder builds a DAWG out of words inserted in alphabetical order,
+// merging the suffix subtrees of the previous word as soon as they can't
+// change anymore (Daciuk et al., "Incremental Construction of Minimal Acyclic
+// Finite-State Automata").
+type dawgBuilder struct {
+	nodes []builderNode
+	// register maps the signature of every merged node to the node.
+	register map[string]int
+	// unchecked are the edges along the previous word whose targets haven't
+	// been merged yet.
+	unchecked []uncheckedEdge
+	previous  string
+}
+
+type builderNode struct {
+	final bool
+	edges []builderEdge
+}
+
+type builderEdge struct {
+	letter byte
+	target int
+}
+
+type uncheckedEdge struct {
+	parent int
+	target int
+}
+
+func newDAWGBuilder() *dawgBuilder {
+	return &dawgBuilder{
+		nodes:    []builderNode{{}},
+		register: map[string]int{},
+	}
+}
+
+func (b *dawgBuilder) insert(word string) {
+	common := 0
+	for common < len(word) && common < len(b.previous) && word[common] == b.previous[common] {
+		common++
+	}
+	b.minimize(common)
+
+	node := 0
+	if len(b.unchecked) > 0 {
+		node = b.unchecked[len(b.unchecked)-1].target
+	}
+	for i := common; i < len(word); i++ {
+		b.nodes = append(b.nodes, builderNode{})
+		target := len(b.nodes) - 1
+		b.nodes[node].edges = append(b.nodes[node].edges, builderEdge{letter: word[i], target: target})
+		b.unchecked = append(b.unchecked, uncheckedEdge{parent: node, target: target})
+		node = target
+	}
+	b.nodes[node].final = true
+	b.previous = word
+}
+
+// minimize merges the targets of the unchecked edges below depth downTo with
+// identical nodes already registered.
+func (b *dawgBuilder) minimize(downTo int) {
+	for len(b.unchecked) > downTo {
+		edge := b.unchecked[len(b.unchecked)-1]
+		signature := b.signature(edge.target)
+		if existing, ok := b.register[signature]; ok {
+			edges := b.nodes[edge.parent].edges
+			edges[len(edges)-1].target = existing
+		} else {
+			b.register[signature] = edge.target
+		}
+		b.unchecked = b.unchecked[:len(b.unchecked)-1]
+	}
+}
+
+// signature identifies a node by its finality and its edges, whose targets
+// are already merged.
+func (b *dawgBuilder) signature(node int) string {
+	signature := make([]byte, 1, 1+5*len(b.nodes[node].edges))
+	if b.nodes[node].final {
+		signature[0] = 1
+	}
+	for _, edge := range b.nodes[node].edges {
+		signature = append(signature, edge.letter)
+		signature = binary.LittleEndian.AppendUint32(signature, uint32(edge.target))
+	}
+	return string(signature)
+}
+
+// build merges the remaining nodes and lays out the nodes reachable from the
+// root in compact arrays, the root first.
+func (b *dawgBuilder) build() DAWG {
+	b.minimize(0)
+
+	d := DAWG{}
+	ids := map[int]int32{}
+	var visit func(node int) int32
+	visit = func(node int) int32 {
+		if id, ok := ids[node]; ok {
+			return id
+		}
+		id := int32(len(d.nodes))
+		ids[node] = id
+		d.nodes = append(d.nodes, dawgNode{})
+
+		n := dawgNode{final: b.nodes[node].final}
+		if n.final {
+			n.lengths, n.count = 1, 1
+		}
+		targets := make([]int32, len(b.nodes[node].edges))
+		for i, edge := range b.nodes[node].edges {
+			targets[i] = visit(edge.target)
+			child := d.nodes[targets[i]]
+			n.count += child.count
+			n.lengths |= child.lengths<<1 | child.lengths&(1<<maxLengthBit)
+		}
+		n.firstEdge = int32(len(d.edges))
+		n.edgeCount = uint16(len(targets))
+		for i, edge := range b.nodes[node].edges {
+			d.edges = append(d.edges, dawgEdge{target: targets[i], letter: edge.letter})
+		}
+		d.nodes[id] = n
+		return id
+	}
+	visit(0)
+
+	d.nodes = slices.Clip(d.nodes)
+	d.edges = slices.Clip(d.edges)
+	return d
+}
@@ -0,0 +1,200 @@
+package dictionary_test
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDAWG(t *testing.T) {
+	dawg := dictionary.NewDAWGFromWords([]string{"car", "cart", "cat", "dog", "car", "do"})
+
+	assert.Equal(t, 5, dawg.Len())
+	assert.True(t, dawg.Contains("cart"))
+	assert.True(t, dawg.Contains("do"))
+	assert.False(t, dawg.Contains("ca"))
+	assert.False(t, dawg.Contains("cars"))
+	assert.True(t, dawg.HasPrefix("ca"))
+	assert.True(t, dawg.HasPrefix(""))
+	assert.False(t, dawg.HasPrefix("cb"))
+
+	for index, word := range []string{"car", "cart", "cat", "do", "dog"} {
+		assert.Equal(t, word, dawg.Word(index))
+	}
+	assert.Equal(t, []string{"car", "cat", "dog"}, words(dawg, "___"))
+	assert.Equal(t, []string{"car", "cat"}, words(dawg, "ca_"))
+	assert.Equal(t, []string{"cart"}, words(dawg, "___t"))
+	assert.Empty(t, words(dawg, "_____"))
+
+	empty := dictionary.NewDAWGFromWords(nil)
+	assert.False(t, empty.Contains("a"))
+	assert.Empty(t, empty.Candidates([]byte{0}))
+	assert.Panics(t, func() { empty.Word(0) })
+	assert.Panics(t, func() { dawg.Word(5) })
+	assert.Panics(t, func() { dawg.Word(-1) })
+}
+
+func TestDAWGMatchesWordDictionary(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	dawg := dictionary.NewDAWG()
+	random := rand.New(rand.NewSource(1))
+
+	for _, pattern := range samplePatterns(wordDict.AllWords, 200, random) {
+		assert.Equal(t, words(wordDict, pattern), words(dawg, pattern), pattern)
+	}
+	for _, i := range random.Perm(len(wordDict.AllWords))[:200] {
+		word := wordDict.AllWords[i]
+		assert.True(t, dawg.Contains(word))
+		assert.Equal(t, wordDict.Contains(word+"q"), dawg.Contains(word+"q"))
+	}
+}
+
+// samplePatterns blanks out about half the letters of random words, written
+// with '_' for unknown letters.
+func samplePatterns(words []string, count int, random *rand.Rand) []string {
+	patterns := []string{}
+	for range count {
+		pattern := []byte(words[random.Intn(len(words))])
+		for i := range pattern {
+			if random.Intn(2) == 0 {
+				pattern[i] = '_'
+			}
+		}
+		patterns = append(patterns, string(pattern))
+	}
+	return patterns
+}
+
+// syntheticWords generates count distinct words with the letter pairs and
+// lengths of the given words, standing in for a large word list.
+func syntheticWords(words []string, count int, random *rand.Rand) []string {
+	next := map[byte][]byte{}
+	for _, word := range words {
+		previous := byte('^')
+		for i := range len(word) {
+			next[previous] = append(next[previous], word[i])
+			previous = word[i]
+		}
+	}
+
+	seen := map[string]bool{}
+	generated := make([]string, 0, count)
+	for len(generated) < count {
+		length := len(words[random.Intn(len(words))])
+		word := make([]byte, length)
+		previous := byte('^')
+		for i := range word {
+			letters := next[previous]
+			if len(letters) == 0 {
+				letters = next['^']
+			}
+			word[i] = letters[random.Intn(len(letters))]
+			previous = word[i]
+		}
+		if !seen[string(word)] {
+			seen[string(word)] = true
+			generated = append(generated, string(word))
+		}
+	}
+	return generated
+}
+
+type wordList struct {
+	name  string
+	words []string
+}
+
+func benchmarkWordLists() []wordList {
+	embedded := dictionary.NewWordDictionary().AllWords
+	return []wordList{
+		{name: "words.txt", words: embedded},
+		{name: "300k", words: syntheticWords(embedded, 300000, rand.New(rand.NewSource(1)))},
+	}
+}
+
+type wordSourceBuilder struct {
+	name  string
+	build func(words []string) dictionary.WordSource
+}
+
+var wordSourceBuilders = []wordSourceBuilder{
+	{name: "WordDictionary", build: func(words []string) dictionary.WordSource {
+		return dictionary.NewWordDictionaryFromWords(words)
+	}},
+	{name: "DAWG", build: func(words []string) dictionary.WordSource {
+		return dictionary.NewDAWGFromWords(words)
+	}},
+}
+
+// heapSize returns the heap memory retained by the value built.
+func heapSize(build func() dictionary.WordSource) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	source := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(source)
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+func BenchmarkBuild(b *testing.B) {
+	for _, list := range benchmarkWordLists() {
+		for _, builder := range wordSourceBuilders {
+			b.Run(fmt.Sprintf("%s/%s", builder.name, list.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					builder.build(list.words)
+				}
+				b.ReportMetric(float64(heapSize(func() dictionary.WordSource {
+					return builder.build(list.words)
+				})), "heap-bytes")
+			})
+		}
+	}
+}
+
+func BenchmarkCandidates(b *testing.B) {
+	for _, list := range benchmarkWordLists() {
+		patterns := [][]byte{}
+		for _, pattern := range samplePatterns(list.words, 1000, rand.New(rand.NewSource(1))) {
+			patterns = append(patterns, []byte(pattern))
+			for i := range pattern {
+				if pattern[i] == '_' {
+					patterns[len(patterns)-1][i] = 0
+				}
+			}
+		}
+		for _, builder := range wordSourceBuilders {
+			source := builder.build(list.words)
+			b.Run(fmt.Sprintf("%s/%s", builder.name, list.name), func(b *testing.B) {
+				for i := range b.N {
+					source.Candidates(patterns[i%len(patterns)])
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	for _, list := range benchmarkWordLists() {
+		random := rand.New(rand.NewSource(1))
+		lookups := slices.Clone(list.words)
+		random.Shuffle(len(lookups), func(i, j int) {
+			lookups[i], lookups[j] = lookups[j], lookups[i]
+		})
+		for _, builder := range wordSourceBuilders {
+			source := builder.build(list.words)
+			b.Run(fmt.Sprintf("%s/%s", builder.name, list.name), func(b *testing.B) {
+				for i := range b.N {
+					source.Contains(lookups[i%len(lookups)])
+				}
+			})
+		}
+	}
+}
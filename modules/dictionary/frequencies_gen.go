@@ -0,0 +1,87 @@
+//go:build ignore
+
+// frequencies_gen.go writes frequencies.txt: the words of words.txt ranked
+// from the most common to the rarest, after the English list of zxcvbn-go,
+// which ranks words by how often they are used in television and films
+// according to the frequency lists of Wiktionary. See NOTICE for the licenses.
+//
+// It is run by go generate and needs network access to download the module.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// source is the module holding the frequency list, pinned to the version the
+// embedded frequencies were generated from
+const source = "github.com/nbutton23/zxcvbn-go@v0.0.0-20210217022336-fa2cb2858354"
+
+func main() {
+	list, err := sourceList()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := os.ReadFile("words.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dictionary := map[string]bool{}
+	for _, word := range strings.Fields(string(data)) {
+		dictionary[word] = true
+	}
+
+	file, err := os.Create("frequencies.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	// words missing from the dictionary are skipped, and repeated words keep
+	// their first rank
+	written := map[string]bool{}
+	for _, word := range list {
+		word = strings.ToLower(word)
+		if dictionary[word] && !written[word] {
+			written[word] = true
+			fmt.Fprintln(writer, word)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// sourceList downloads the source module and returns its English list, from
+// the most common word to the rarest
+func sourceList() ([]string, error) {
+	output, err := exec.Command("go", "mod", "download", "-json", source).Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot download %s: %w", source, err)
+	}
+	var module struct {
+		Dir string
+	}
+	if err := json.Unmarshal(output, &module); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(module.Dir, "data", "data", "English.json"))
+	if err != nil {
+		return nil, err
+	}
+	var english struct {
+		List []string
+	}
+	if err := json.Unmarshal(data, &english); err != nil {
+		return nil, err
+	}
+	return english.List, nil
+}
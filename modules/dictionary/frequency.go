@@ -0,0 +1,111 @@
+package dictionary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FrequencyTiers are the ranks starting every frequency tier but the first:
+// tier 0 holds the 1000 most common words, tier 1 the next 2000 words, and so
+// on, the last tier holding the rarest words.
+var FrequencyTiers = []int{1000, 3000, 10000}
+
+// FrequencySource is a TieredSource ranking its words by frequency. Ranked
+// returns the number of ranked words and Common the source of the n most
+// common ones.
+type FrequencySource interface {
+	TieredSource
+	Ranked() int
+	Common(n int) WordSource
+}
+
+// LevelWords returns the number of most common words a crossword of the given
+// level is filled with: the 3000 most common words for easy crosswords, 10000
+// for medium ones and all the words, 0, for hard ones.
+func LevelWords(level string) (int, error) {
+	switch level {
+	case "easy":
+		return FrequencyTiers[1], nil
+	case "medium":
+		return FrequencyTiers[2], nil
+	case "hard":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown level %q", level)
+}
+
+// WithFrequencies returns the dictionary ranking its words by the frequency
+// list read from r: one word per line, from the most common to the rarest,
+// any field after the word being ignored so that lists of words followed by
+// their counts can be used as is. Words of the list missing from the
+// dictionary are skipped, and words of the dictionary missing from the list
+// are left unranked. The list replaces any previous ranking, such as the
+// embedded one of NewWordDictionary.
+func (wd WordDictionary) WithFrequencies(r io.Reader) (WordDictionary, error) {
+	ranks := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		word := strings.ToLower(fields[0])
+		if _, ranked := ranks[word]; ranked || !wd.Contains(word) {
+			continue
+		}
+		ranks[word] = len(ranks)
+	}
+	if err := scanner.Err(); err != nil {
+		return wd, fmt.Errorf("cannot read frequencies: %w", err)
+	}
+	wd.ranks = ranks
+	return wd, nil
+}
+
+// Rank returns the frequency rank of a word, 0 for the most common one, and
+// false if the word isn't ranked.
+func (wd WordDictionary) Rank(word string) (int, bool) {
+	rank, ok := wd.ranks[word]
+	return rank, ok
+}
+
+func (wd WordDictionary) Tier(word string) (int, bool) {
+	rank, ok := wd.ranks[word]
+	if !ok {
+		return 0, false
+	}
+	tier := 0
+	for tier < len(FrequencyTiers) && rank >= FrequencyTiers[tier] {
+		tier++
+	}
+	return tier, true
+}
+
+// Tiers returns the number of frequency tiers, 0 if the dictionary has no
+// frequencies.
+func (wd WordDictionary) Tiers() int {
+	if wd.ranks == nil {
+		return 0
+	}
+	return len(FrequencyTiers) + 1
+}
+
+func (wd WordDictionary) Ranked() int {
+	return len(wd.ranks)
+}
+
+// Common returns the dictionary of the n most common words, keeping their
+// ranks.
+func (wd WordDictionary) Common(n int) WordSource {
+	words := []string{}
+	for _, word := range wd.AllWords {
+		if rank, ok := wd.ranks[word]; ok && rank < n {
+			words = append(words, word)
+		}
+	}
+	common := NewWordDictionaryFromWords(words)
+	common.ranks = wd.ranks
+	return common
+}
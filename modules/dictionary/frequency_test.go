@@ -0,0 +1,79 @@
+package dictionary_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFrequencies(t *testing.T) {
+	wordDict := dictionary.NewWordDictionaryFromWords([]string{"cat", "dog", "cow", "emu"})
+	assert.Equal(t, 0, wordDict.Tiers())
+
+	wordDict, err := wordDict.WithFrequencies(strings.NewReader("the 900\nDog 120\n\ncat 80\ndog 10\ncow\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, wordDict.Ranked())
+	assert.Equal(t, len(dictionary.FrequencyTiers)+1, wordDict.Tiers())
+
+	rank, ok := wordDict.Rank("cat")
+	assert.True(t, ok)
+	assert.Equal(t, 1, rank)
+	tier, ok := wordDict.Tier("cow")
+	assert.True(t, ok)
+	assert.Equal(t, 0, tier)
+	_, ok = wordDict.Tier("emu")
+	assert.False(t, ok)
+
+	common := wordDict.Common(2)
+	assert.True(t, common.Contains("dog"))
+	assert.True(t, common.Contains("cat"))
+	assert.False(t, common.Contains("cow"))
+	assert.False(t, common.Contains("emu"))
+}
+
+func TestEmbeddedFrequencies(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	assert.Equal(t, len(dictionary.FrequencyTiers)+1, wordDict.Tiers())
+	assert.Greater(t, wordDict.Ranked(), dictionary.FrequencyTiers[len(dictionary.FrequencyTiers)-1])
+
+	tier, ok := wordDict.Tier("the")
+	assert.True(t, ok)
+	assert.Equal(t, 0, tier)
+
+	// a frequency list replaces the embedded one
+	wordDict, err := wordDict.WithFrequencies(strings.NewReader("zebra\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, wordDict.Ranked())
+	rank, ok := wordDict.Rank("zebra")
+	assert.True(t, ok)
+	assert.Equal(t, 0, rank)
+}
+
+func TestFrequencyTiers(t *testing.T) {
+	ranked := []string{}
+	list := strings.Builder{}
+	for i := range 12000 {
+		word := fmt.Sprintf("w%05d", i)
+		ranked = append(ranked, word)
+		list.WriteString(word + "\n")
+	}
+	wordDict, err := dictionary.NewWordDictionaryFromWords(ranked).WithFrequencies(strings.NewReader(list.String()))
+	assert.NoError(t, err)
+
+	for word, expected := range map[string]int{"w00000": 0, "w00999": 0, "w01000": 1, "w02999": 1, "w03000": 2, "w10000": 3} {
+		tier, ok := wordDict.Tier(word)
+		assert.True(t, ok)
+		assert.Equal(t, expected, tier, word)
+	}
+
+	for level, expected := range map[string]int{"easy": 3000, "medium": 10000, "hard": 0} {
+		n, err := dictionary.LevelWords(level)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, n)
+	}
+	_, err = dictionary.LevelWords("kids")
+	assert.Error(t, err)
+}
@@ -2,48 +2,91 @@ package dictionary
 
 import (
 	_ "embed"
-	"slices"
+	"math/bits"
 	"strings"
 )
 
 //go:embed words.txt
 var words string
 
+// frequencies ranks the embedded words from the most common to the rarest, as
+// used in television and films according to the frequency lists of
+// Wiktionary. The words missing from it are left unranked. It is generated by
+// frequencies_gen.go, see NOTICE for its sources and licenses.
+//
+//go:generate go run frequencies_gen.go
+//go:embed frequencies.txt
+var frequencies string
+
 type WordDictionary struct {
 	AllWords  []string
 	wordSet   map[string]struct{}
 	lengthMap map[int][]int
-	letterMap map[wordDictionaryKey]map[int]struct{}
+	letterMap map[wordDictionaryKey]wordBitset
+	// ranks are the frequency ranks of the words, nil if the dictionary has
+	// no frequencies.
+	ranks map[string]int
 }
 
+// wordDictionaryKey identifies the words of a given length having a given
+// letter at a given position.
 type wordDictionaryKey struct {
+	length int
 	letter byte
 	pos    int
 }
 
+// wordBitset is a bitset over the words of a given length, bit i standing for
+// the word lengthMap[length][i].
+type wordBitset []uint64
+
+func newWordBitset(size int) wordBitset {
+	return make(wordBitset, (size+63)/64)
+}
+
+func (s wordBitset) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+// NewWordDictionary returns the dictionary of the embedded word list, ranked
+// by the embedded frequency list. WithFrequencies ranks it by another list.
 func NewWordDictionary() WordDictionary {
+	// reading a string can't fail
+	wd, _ := NewWordDictionaryFromWords(strings.Fields(words)).WithFrequencies(strings.NewReader(frequencies))
+	return wd
+}
+
+// NewWordDictionaryFromWords returns a dictionary of the given words, such as
+// numbers for cross-number puzzles. Words can't contain '.' or 0, which stand
+// for blank and empty squares in a crossword.
+func NewWordDictionaryFromWords(words []string) WordDictionary {
 	dict := WordDictionary{
 		AllWords:  []string{},
 		wordSet:   map[string]struct{}{},
 		lengthMap: map[int][]int{},
-		letterMap: map[wordDictionaryKey]map[int]struct{}{},
+		letterMap: map[wordDictionaryKey]wordBitset{},
 	}
 
-	for wordIndex, word := range strings.Fields(words) {
+	for wordIndex, word := range words {
 		dict.AllWords = append(dict.AllWords, word)
 		dict.wordSet[word] = struct{}{}
 		dict.lengthMap[len(word)] = append(dict.lengthMap[len(word)], wordIndex)
-		for i := range len(word) {
-			key := wordDictionaryKey{letter: word[i], pos: i}
-			if _, exists := dict.letterMap[key]; !exists {
-				dict.letterMap[key] = map[int]struct{}{}
+	}
+
+	for length, indexes := range dict.lengthMap {
+		for i, wordIndex := range indexes {
+			word := dict.AllWords[wordIndex]
+			for pos := range len(word) {
+				key := wordDictionaryKey{length: length, letter: word[pos], pos: pos}
+				if _, exists := dict.letterMap[key]; !exists {
+					dict.letterMap[key] = newWordBitset(len(indexes))
+				}
+				dict.letterMap[key].add(i)
 			}
-			dict.letterMap[key][wordIndex] = struct{}{}
 		}
 	}
 
 	return dict
-
 }
 
 func (wd WordDictionary) Contains(word string) bool {
@@ -51,19 +94,46 @@ func (wd WordDictionary) Contains(word string) bool {
 	return exists
 }
 
+func (wd WordDictionary) Word(index int) string {
+	return wd.AllWords[index]
+}
+
 func (wd WordDictionary) Candidates(word []byte) []int {
-	candidates := make([]int, len(wd.lengthMap[len(word)]))
-	copy(candidates, wd.lengthMap[len(word)])
+	indexes := wd.lengthMap[len(word)]
 
+	var candidates wordBitset
 	for i, letter := range word {
-		if letter != 0 {
-			key := wordDictionaryKey{letter: letter, pos: i}
-			currentSet := wd.letterMap[key]
-			candidates = slices.DeleteFunc(candidates, func(e int) bool {
-				_, exists := currentSet[e]
-				return !exists
-			})
+		if letter == 0 {
+			continue
+		}
+		key := wordDictionaryKey{length: len(word), letter: letter, pos: i}
+		letterSet, exists := wd.letterMap[key]
+		if !exists {
+			return []int{}
+		}
+		if candidates == nil {
+			candidates = make(wordBitset, len(letterSet))
+			copy(candidates, letterSet)
+			continue
+		}
+		for block := range candidates {
+			candidates[block] &= letterSet[block]
+		}
+	}
+
+	if candidates == nil {
+		result := make([]int, len(indexes))
+		copy(result, indexes)
+		return result
+	}
+
+	result := []int{}
+	for block, bitmap := range candidates {
+		for bitmap != 0 {
+			bit := bits.TrailingZeros64(bitmap)
+			result = append(result, indexes[block*64+bit])
+			bitmap &= bitmap - 1
 		}
 	}
-	return candidates
+	return result
 }
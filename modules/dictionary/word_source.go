@@ -0,0 +1,145 @@
+package dictionary
+
+// WordSource is a set of words the generator can pick from. Candidates returns
+// the indexes of the words matching a pattern, 0 standing for any letter, and
+// Word returns the word of an index. Indexes are non-negative.
+type WordSource interface {
+	Contains(word string) bool
+	Candidates(pattern []byte) []int
+	Word(index int) string
+}
+
+// WordScorer is implemented by word sources preferring some words over others.
+// Score returns the positive weight of the word of an index, candidates being
+// picked with a probability proportional to their weight. Words of sources
+// without scores are equally likely.
+type WordScorer interface {
+	Score(index int) float64
+}
+
+// TieredSource is implemented by word sources knowing how common their words
+// are. Tier returns the frequency tier of a word, from 0 for the most common
+// words to Tiers()-1 for the rarest, and false if the word isn't ranked.
+type TieredSource interface {
+	Tier(word string) (int, bool)
+	Tiers() int
+}
+
+// score returns the weight of the word of an index, 1 if the source doesn't
+// score its words.
+func score(source WordSource, index int) float64 {
+	if scorer, ok := source.(WordScorer); ok {
+		return scorer.Score(index)
+	}
+	return 1
+}
+
+type filteredSource struct {
+	WordSource
+	keep func(word string) bool
+}
+
+// scoredFilteredSource is a filteredSource keeping the scores of its source.
+type scoredFilteredSource struct {
+	filteredSource
+}
+
+// Filtered returns the words of the source for which keep returns true. keep
+// is called on every candidate, so it should be cheap.
+func Filtered(source WordSource, keep func(word string) bool) WordSource {
+	filtered := filteredSource{WordSource: source, keep: keep}
+	if _, ok := source.(WordScorer); ok {
+		return scoredFilteredSource{filtered}
+	}
+	return filtered
+}
+
+func (s filteredSource) Contains(word string) bool {
+	return s.keep(word) && s.WordSource.Contains(word)
+}
+
+func (s filteredSource) Candidates(pattern []byte) []int {
+	candidates := []int{}
+	for _, index := range s.WordSource.Candidates(pattern) {
+		if s.keep(s.WordSource.Word(index)) {
+			candidates = append(candidates, index)
+		}
+	}
+	return candidates
+}
+
+func (s scoredFilteredSource) Score(index int) float64 {
+	return score(s.WordSource, index)
+}
+
+// mergedSource interleaves the indexes of its sources: index i of source j is
+// index i*len(sources)+j.
+type mergedSource struct {
+	sources []WordSource
+}
+
+// Merged returns the words of all the sources. A word found in several sources
+// is only a candidate from the first one, and keeps its score there. Merging no
+// sources gives an empty source.
+func Merged(sources ...WordSource) WordSource {
+	if len(sources) == 0 {
+		return NewWordDictionaryFromWords(nil)
+	}
+	return mergedSource{sources: sources}
+}
+
+func (s mergedSource) Contains(word string) bool {
+	for _, source := range s.sources {
+		if source.Contains(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s mergedSource) Candidates(pattern []byte) []int {
+	candidates := []int{}
+	for j, source := range s.sources {
+		for _, index := range source.Candidates(pattern) {
+			if s.containedBefore(j, source.Word(index)) {
+				continue
+			}
+			candidates = append(candidates, index*len(s.sources)+j)
+		}
+	}
+	return candidates
+}
+
+func (s mergedSource) Word(index int) string {
+	return s.sources[index%len(s.sources)].Word(index / len(s.sources))
+}
+
+func (s mergedSource) Score(index int) float64 {
+	return score(s.sources[index%len(s.sources)], index/len(s.sources))
+}
+
+// containedBefore reports whether the word is in one of the sources preceding
+// the source of index j.
+func (s mergedSource) containedBefore(j int, word string) bool {
+	for _, source := range s.sources[:j] {
+		if source.Contains(word) {
+			return true
+		}
+	}
+	return false
+}
+
+type weightedSource struct {
+	WordSource
+	weight func(word string) float64
+}
+
+// Weighted scores the words of the source by weight, which must be positive,
+// multiplied by their score in the source if it has one.
+func Weighted(source WordSource, weight func(word string) float64) WordSource {
+	return weightedSource{WordSource: source, weight: weight}
+}
+
+func (s weightedSource) Score(index int) float64 {
+	return s.weight(s.WordSource.Word(index)) * score(s.WordSource, index)
+}
@@ -0,0 +1,78 @@
+package dictionary_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+// words returns the candidates of the source matching the pattern, written
+// with '_' for unknown letters.
+func words(source dictionary.WordSource, pattern string) []string {
+	value := []byte(strings.ReplaceAll(pattern, "_", "\x00"))
+	words := []string{}
+	for _, index := range source.Candidates(value) {
+		words = append(words, source.Word(index))
+	}
+	slices.Sort(words)
+	return words
+}
+
+func TestFiltered(t *testing.T) {
+	source := dictionary.Filtered(dictionary.NewWordDictionaryFromWords([]string{"cat", "cot", "dog"}), func(word string) bool {
+		return word != "cot"
+	})
+
+	assert.True(t, source.Contains("cat"))
+	assert.False(t, source.Contains("cot"))
+	assert.Equal(t, []string{"cat"}, words(source, "c__"))
+	assert.Equal(t, []string{"cat", "dog"}, words(source, "___"))
+	_, scored := source.(dictionary.WordScorer)
+	assert.False(t, scored)
+}
+
+func TestMerged(t *testing.T) {
+	source := dictionary.Merged(
+		dictionary.NewWordDictionaryFromWords([]string{"cat", "dog"}),
+		dictionary.Weighted(dictionary.NewWordDictionaryFromWords([]string{"cat", "cow", "emu"}), func(word string) float64 {
+			return 2
+		}),
+	)
+
+	assert.True(t, source.Contains("emu"))
+	assert.False(t, source.Contains("owl"))
+	assert.Equal(t, []string{"cat", "cow", "dog", "emu"}, words(source, "___"))
+	assert.Equal(t, []string{"cat", "cow"}, words(source, "c__"))
+
+	scorer, ok := source.(dictionary.WordScorer)
+	assert.True(t, ok)
+	for _, index := range source.Candidates([]byte("c\x00\x00")) {
+		if source.Word(index) == "cat" {
+			assert.Equal(t, 1.0, scorer.Score(index))
+		} else {
+			assert.Equal(t, 2.0, scorer.Score(index))
+		}
+	}
+
+	empty := dictionary.Merged()
+	assert.False(t, empty.Contains("cat"))
+	assert.Empty(t, words(empty, "___"))
+}
+
+func TestWeighted(t *testing.T) {
+	source := dictionary.Weighted(dictionary.NewWordDictionaryFromWords([]string{"cat", "ox"}), func(word string) float64 {
+		return float64(len(word))
+	})
+	source = dictionary.Weighted(source, func(word string) float64 {
+		return 0.5
+	})
+
+	assert.True(t, source.Contains("ox"))
+	scorer := source.(dictionary.WordScorer)
+	for _, index := range source.Candidates([]byte{0, 0, 0}) {
+		assert.Equal(t, 1.5, scorer.Score(index))
+	}
+}
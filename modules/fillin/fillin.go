@@ -0,0 +1,296 @@
+package fillin
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+)
+
+// maxSolverNodes bounds the number of partial fills explored by the solver,
+// beyond which uniqueness is considered unproven.
+const maxSolverNodes = 100000
+
+// ErrNotUnique is returned by NewFillIn when the answers may fit the grid in
+// more than one way, the solver running out of budget before proving the
+// solution unique.
+var ErrNotUnique = errors.New("the fill-in puzzle can't be proven to have a unique solution")
+
+// WordGroup lists the answers of a given length, in alphabetical order.
+type WordGroup struct {
+	Length int
+	Words  []string
+}
+
+// FillIn is a clue-less puzzle giving the solver the empty grid, a few given
+// letters and the list of its answers grouped by length.
+type FillIn struct {
+	grid   *crossword.Crossword
+	givens []bool
+}
+
+// NewFillIn turns a filled crossword into a fill-in puzzle, giving letters of
+// the grid until its answers fit the grid in only one way, or returns
+// ErrNotUnique when that can't be proven.
+func NewFillIn(c *crossword.Crossword) (*FillIn, error) {
+	f := &FillIn{
+		grid:   c,
+		givens: make([]bool, c.Rows()*c.Columns()),
+	}
+	for {
+		solutions, complete := newSolver(f).solve(2)
+		if complete && len(solutions) <= 1 {
+			return f, nil
+		}
+		square := f.disambiguation(solutions)
+		if square == -1 {
+			return nil, ErrNotUnique
+		}
+		f.givens[square] = true
+	}
+}
+
+// Accept reports whether the crossword makes a fill-in puzzle with a proven
+// unique solution, for CrosswordConfig.Accept to regenerate the other ones.
+func Accept(c *crossword.Crossword) bool {
+	_, err := NewFillIn(c)
+	return err == nil
+}
+
+// Solution returns the filled grid.
+func (f *FillIn) Solution() *crossword.Crossword {
+	return f.grid
+}
+
+// Puzzle returns the grid given to the solver, whose squares are empty apart
+// from the given letters.
+func (f *FillIn) Puzzle() *crossword.Crossword {
+	puzzle := crossword.NewGrid(f.grid.Rows(), f.grid.Columns())
+	for letter := crossword.CrosswordLetter(f.grid); letter != nil; letter = letter.Next() {
+		if letter.IsBlank() || f.IsGiven(letter.Row(), letter.Column()) {
+			crossword.CrosswordLetterAt(puzzle, letter.Row(), letter.Column()).SetValue(letter.GetValue())
+		}
+		if bars := letter.Bars(); bars != 0 {
+			puzzle.AddBar(letter.Row(), letter.Column(), bars)
+		}
+		if letter.IsMasked() {
+			puzzle.AddMask(letter.Row(), letter.Column())
+		}
+	}
+	return puzzle
+}
+
+// Words returns the answers of the grid grouped by length, shortest first.
+func (f *FillIn) Words() []WordGroup {
+	groups := []WordGroup{}
+	for word := crossword.Word(f.grid); word != nil; word = word.Next() {
+		value := string(word.GetValue())
+		i, found := slices.BinarySearchFunc(groups, len(value), func(group WordGroup, length int) int {
+			return group.Length - length
+		})
+		if !found {
+			groups = slices.Insert(groups, i, WordGroup{Length: len(value)})
+		}
+		groups[i].Words = append(groups[i].Words, value)
+	}
+	for _, group := range groups {
+		slices.Sort(group.Words)
+	}
+	return groups
+}
+
+// IsGiven reports whether the letter of the square is given to the solver.
+func (f *FillIn) IsGiven(row, column int) bool {
+	return f.givens[row*f.grid.Columns()+column]
+}
+
+// Givens returns the number of letters given to the solver.
+func (f *FillIn) Givens() int {
+	givens := 0
+	for _, given := range f.givens {
+		if given {
+			givens++
+		}
+	}
+	return givens
+}
+
+// IsUnique reports whether the answers fit the grid in only one way, given
+// letters included. It returns false when uniqueness can't be established
+// within the search budget of the solver.
+func (f *FillIn) IsUnique() bool {
+	solutions, complete := newSolver(f).solve(2)
+	return complete && len(solutions) == 1
+}
+
+// disambiguation returns the next square to give: the first square two
+// solutions disagree on or, failing that, the first letter of the longest
+// answer without any given letter. It returns -1 if there's nothing left to
+// give.
+func (f *FillIn) disambiguation(solutions []string) int {
+	if len(solutions) == 2 {
+		rows := strings.Split(solutions[0], "\n")
+		otherRows := strings.Split(solutions[1], "\n")
+		for row := range rows {
+			for column := range len(rows[row]) {
+				if rows[row][column] != otherRows[row][column] {
+					return row*f.grid.Columns() + column
+				}
+			}
+		}
+	}
+
+	square, length := -1, 0
+	for word := crossword.Word(f.grid); word != nil; word = word.Next() {
+		given := false
+		for letter := crossword.WordLetter(word); letter != nil; letter = letter.Next() {
+			given = given || f.IsGiven(letter.Row(), letter.Column())
+		}
+		if !given && word.GetLength() > length {
+			square, length = word.GetPos(), word.GetLength()
+		}
+	}
+	return square
+}
+
+// solver fits the answers of a fill-in into its puzzle grid, each answer being
+// used once. It fills one word at a time, always the one with the fewest
+// answers left that fit.
+type solver struct {
+	puzzle   *crossword.Crossword
+	words    []*crossword.WordRef
+	assigned []int
+	answers  []string
+	used     []bool
+	nodes    int
+}
+
+func newSolver(f *FillIn) *solver {
+	s := &solver{puzzle: f.Puzzle()}
+	for word := crossword.Word(s.puzzle); word != nil; word = word.Next() {
+		s.words = append(s.words, word)
+		s.assigned = append(s.assigned, -1)
+	}
+	for _, group := range f.Words() {
+		s.answers = append(s.answers, group.Words...)
+	}
+	s.used = make([]bool, len(s.answers))
+	return s
+}
+
+// solve returns up to limit solutions and whether the search was exhaustive.
+func (s *solver) solve(limit int) ([]string, bool) {
+	solutions := []string{}
+	complete := s.search(limit, &solutions)
+	return solutions, complete
+}
+
+func (s *solver) search(limit int, solutions *[]string) bool {
+	s.nodes++
+	if s.nodes > maxSolverNodes {
+		return false
+	}
+
+	// words completed by their crossings use up their answer
+	completed := []int{}
+	defer func() {
+		for _, i := range completed {
+			s.unassign(i)
+		}
+	}()
+	for i, word := range s.words {
+		value := word.GetValue()
+		if s.assigned[i] != -1 || slices.Contains(value, 0) {
+			continue
+		}
+		answer := s.unusedAnswer(value)
+		if answer == -1 {
+			return true
+		}
+		s.assign(i, answer)
+		completed = append(completed, i)
+	}
+
+	next, candidates := -1, []int(nil)
+	for i, word := range s.words {
+		if s.assigned[i] != -1 {
+			continue
+		}
+		wordCandidates := s.candidates(word.GetValue())
+		if next == -1 || len(wordCandidates) < len(candidates) {
+			next, candidates = i, wordCandidates
+		}
+		if len(candidates) == 0 {
+			return true
+		}
+	}
+	if next == -1 {
+		*solutions = append(*solutions, s.puzzle.String())
+		return true
+	}
+
+	word := s.words[next]
+	previous := word.GetValue()
+	for _, candidate := range candidates {
+		word.SetValue([]byte(s.answers[candidate]))
+		s.assign(next, candidate)
+		complete := s.search(limit, solutions)
+		s.unassign(next)
+		word.SetValue(previous)
+		if !complete {
+			return false
+		}
+		if len(*solutions) >= limit {
+			return true
+		}
+	}
+	return true
+}
+
+func (s *solver) assign(word, answer int) {
+	s.assigned[word] = answer
+	s.used[answer] = true
+}
+
+func (s *solver) unassign(word int) {
+	s.used[s.assigned[word]] = false
+	s.assigned[word] = -1
+}
+
+// candidates returns the unused answers matching the known letters of the
+// word. Repeated answers are only returned once, as trying each of them would
+// find the same solutions again.
+func (s *solver) candidates(value []byte) []int {
+	candidates := []int{}
+	for i, answer := range s.answers {
+		// the answers of a length are sorted, so repeated ones are adjacent
+		if !s.used[i] && matches(answer, value) &&
+			(len(candidates) == 0 || s.answers[candidates[len(candidates)-1]] != answer) {
+			candidates = append(candidates, i)
+		}
+	}
+	return candidates
+}
+
+// unusedAnswer returns the index of an unused answer equal to the word, or -1.
+func (s *solver) unusedAnswer(value []byte) int {
+	for i, answer := range s.answers {
+		if !s.used[i] && answer == string(value) {
+			return i
+		}
+	}
+	return -1
+}
+
+func matches(answer string, value []byte) bool {
+	if len(answer) != len(value) {
+		return false
+	}
+	for i, letter := range value {
+		if letter != 0 && letter != answer[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package fillin_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/fillin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFillIn(t *testing.T) {
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     13,
+		Cols:     13,
+		Threads:  4,
+		WordDict: dictionary.NewWordDictionary(),
+	})
+	assert.NoError(t, err)
+
+	f, err := fillin.NewFillIn(result.Crossword)
+	assert.NoError(t, err)
+	assert.True(t, f.IsUnique())
+	assert.True(t, fillin.Accept(result.Crossword))
+
+	words := 0
+	for word := crossword.Word(result.Crossword); word != nil; word = word.Next() {
+		words++
+	}
+	answers := 0
+	for i, group := range f.Words() {
+		if i > 0 {
+			assert.Greater(t, group.Length, f.Words()[i-1].Length)
+		}
+		assert.True(t, slices.IsSorted(group.Words))
+		for _, word := range group.Words {
+			assert.Len(t, word, group.Length)
+		}
+		answers += len(group.Words)
+	}
+	assert.Equal(t, words, answers)
+
+	givens := 0
+	for letter := crossword.CrosswordLetter(f.Puzzle()); letter != nil; letter = letter.Next() {
+		solution := crossword.CrosswordLetterAt(result.Crossword, letter.Row(), letter.Column())
+		switch {
+		case solution.IsBlank():
+			assert.True(t, letter.IsBlank())
+		case f.IsGiven(letter.Row(), letter.Column()):
+			assert.Equal(t, solution.GetValue(), letter.GetValue())
+			givens++
+		default:
+			assert.True(t, letter.IsEmpty())
+		}
+	}
+	assert.Equal(t, f.Givens(), givens)
+}
+
+func TestNewFillInGivesLetters(t *testing.T) {
+	// the two words can be swapped, so a letter has to be given
+	c, err := crossword.ParseCrossword("bat\n...\ntop")
+	assert.NoError(t, err)
+
+	f, err := fillin.NewFillIn(c)
+	assert.NoError(t, err)
+	assert.True(t, f.IsUnique())
+	assert.Equal(t, 1, f.Givens())
+}
+
+func TestNewFillInRepeatedAnswers(t *testing.T) {
+	// swapping the two answers gives the same grid
+	c, err := crossword.ParseCrossword("top\no..\np..")
+	assert.NoError(t, err)
+
+	f, err := fillin.NewFillIn(c)
+	assert.NoError(t, err)
+	assert.True(t, f.IsUnique())
+}
+
+func TestFillInPuzzleKeepsMask(t *testing.T) {
+	c, err := crossword.ParseCrossword("#bat\n##.o\n#top")
+	assert.NoError(t, err)
+
+	f, err := fillin.NewFillIn(c)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Layout(), f.Puzzle().Layout())
+	assert.True(t, crossword.CrosswordLetterAt(f.Puzzle(), 1, 1).IsMasked())
+}
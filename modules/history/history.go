@@ -0,0 +1,192 @@
+// Package history keeps track of the puzzles already published, so that new
+// crosswords can avoid their answers and layouts.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+)
+
+// Entry records a published crossword.
+type Entry struct {
+	Date    time.Time `json:"date"`
+	Answers []string  `json:"answers"`
+	// Layout is the layout of the crossword (see crossword.Crossword.Layout).
+	Layout string `json:"layout"`
+}
+
+// NewEntry records the answers and the layout of a crossword published on the
+// given date.
+func NewEntry(c *crossword.Crossword, date time.Time) Entry {
+	answers := []string{}
+	for word := crossword.Word(c); word != nil; word = word.Next() {
+		if word.IsFilled() {
+			answers = append(answers, string(word.GetValue()))
+		}
+	}
+	slices.Sort(answers)
+	return Entry{
+		Date:    date,
+		Answers: slices.Compact(answers),
+		Layout:  c.Layout(),
+	}
+}
+
+// Policy is how a crossword treats the answers and layouts of recent entries.
+type Policy int
+
+const (
+	// Forbid excludes them from the crossword.
+	Forbid Policy = iota
+	// Penalize makes them less likely.
+	Penalize
+)
+
+// ParsePolicy returns the policy of the given name, forbid or penalize.
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case "forbid":
+		return Forbid, nil
+	case "penalize":
+		return Penalize, nil
+	}
+	return 0, fmt.Errorf("unknown history policy %q", name)
+}
+
+// Store is a history of published crosswords kept in a file, one JSON entry
+// per line, in the order they were added.
+type Store struct {
+	path    string
+	entries []Entry
+}
+
+// Open reads the store kept in the file at path. A missing file is an empty
+// store, created by the first entry added.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid history entry at %s:%d: %w", path, line, err)
+		}
+		s.entries = append(s.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Entries returns the entries of the store in the order they were added.
+func (s *Store) Entries() []Entry {
+	return s.entries
+}
+
+// Add appends the entries to the store and its file.
+func (s *Store) Add(entries ...Entry) error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return err
+		}
+		s.entries = append(s.entries, entry)
+	}
+	return file.Close()
+}
+
+// Prune removes the entries dated before the given time and returns how many
+// were removed. The file is rewritten as a whole, so that it is left as it was
+// if rewriting it fails.
+func (s *Store) Prune(before time.Time) (int, error) {
+	kept := slices.DeleteFunc(slices.Clone(s.entries), func(entry Entry) bool {
+		return entry.Date.Before(before)
+	})
+	removed := len(s.entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+	encoder := json.NewEncoder(file)
+	for _, entry := range kept {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return 0, err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(file.Name(), s.path); err != nil {
+		return 0, err
+	}
+	s.entries = kept
+	return removed, nil
+}
+
+// Recent returns the answers and the layouts of the entries dated since the
+// given time, without duplicates.
+func (s *Store) Recent(since time.Time) (answers, layouts []string) {
+	for _, entry := range s.entries {
+		if entry.Date.Before(since) {
+			continue
+		}
+		answers = append(answers, entry.Answers...)
+		layouts = append(layouts, entry.Layout)
+	}
+	slices.Sort(answers)
+	slices.Sort(layouts)
+	return slices.Compact(answers), slices.Compact(layouts)
+}
+
+// Apply returns the configuration avoiding the answers and the layouts of the
+// entries dated since the given time, as the policy requires. The required
+// words of the configuration are left alone.
+func (s *Store) Apply(config crossword.CrosswordConfig, since time.Time, policy Policy) crossword.CrosswordConfig {
+	answers, layouts := s.Recent(since)
+	answers = slices.DeleteFunc(answers, func(answer string) bool {
+		return slices.ContainsFunc(config.Require, func(word string) bool {
+			return strings.EqualFold(strings.TrimSpace(word), answer)
+		})
+	})
+	switch policy {
+	case Forbid:
+		config.Exclude = append(slices.Clone(config.Exclude), answers...)
+		config.ExcludeLayouts = append(slices.Clone(config.ExcludeLayouts), layouts...)
+	case Penalize:
+		config.Penalize = append(slices.Clone(config.Penalize), answers...)
+		config.PenalizeLayouts = append(slices.Clone(config.PenalizeLayouts), layouts...)
+	}
+	return config
+}
@@ -0,0 +1,73 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	grid, err := crossword.ParseCrossword(`
+		cat
+		a.o
+		two
+	`)
+	assert.NoError(t, err)
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := history.Open(path)
+	assert.NoError(t, err)
+	assert.Empty(t, store.Entries())
+
+	entry := history.NewEntry(grid, day)
+	assert.Equal(t, []string{"cat", "too", "two"}, entry.Answers)
+	assert.Equal(t, "___\n_._\n___\n", entry.Layout)
+
+	older := history.Entry{Date: day.AddDate(0, 0, -30), Answers: []string{"dog"}, Layout: "__\n__\n"}
+	assert.NoError(t, store.Add(older, entry))
+
+	store, err = history.Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []history.Entry{older, entry}, store.Entries())
+
+	answers, layouts := store.Recent(day.AddDate(0, 0, -7))
+	assert.Equal(t, []string{"cat", "too", "two"}, answers)
+	assert.Equal(t, []string{"___\n_._\n___\n"}, layouts)
+
+	removed, err := store.Prune(day.AddDate(0, 0, -7))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	store, err = history.Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []history.Entry{entry}, store.Entries())
+
+	assert.NoError(t, os.WriteFile(path, []byte("{not json\n"), 0o644))
+	_, err = history.Open(path)
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	assert.NoError(t, err)
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Add(history.Entry{Date: day, Answers: []string{"cat", "dog"}, Layout: "__\n__\n"}))
+
+	config := crossword.CrosswordConfig{Require: []string{"Dog"}}
+	forbidden := store.Apply(config, day, history.Forbid)
+	assert.Equal(t, []string{"cat"}, forbidden.Exclude)
+	assert.Equal(t, []string{"__\n__\n"}, forbidden.ExcludeLayouts)
+	assert.Empty(t, forbidden.Penalize)
+
+	penalized := store.Apply(config, day, history.Penalize)
+	assert.Equal(t, []string{"cat"}, penalized.Penalize)
+	assert.Equal(t, []string{"__\n__\n"}, penalized.PenalizeLayouts)
+	assert.Empty(t, penalized.Exclude)
+
+	assert.Equal(t, config, store.Apply(config, day.AddDate(0, 0, 1), history.Forbid))
+}
@@ -0,0 +1,289 @@
+// Package lint reviews the fill of crosswords, reporting the words an editor
+// would rather avoid.
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// penalties are the penalty points of every severity, indexed by severity.
+var penalties = [...]int{Info: 1, Warning: 3, Error: 10}
+
+type Rule int
+
+const (
+	// UnknownWord reports a word missing from the dictionary.
+	UnknownWord Rule = iota
+	// ObscureWord reports a word of the rarest frequency tier of the
+	// dictionary, or one it doesn't rank.
+	ObscureWord
+	// DuplicateWord reports a word found more than once in the grid.
+	DuplicateWord
+	// SharedRoot reports a word sharing its root with another one, such as
+	// RUN and RUNS.
+	SharedRoot
+	// TwoLetterWords reports a grid with too many two-letter words.
+	TwoLetterWords
+	// Abbreviation reports a word without vowels, such as TV, which is likely
+	// an abbreviation.
+	Abbreviation
+	// RareLetters reports a rare letter, J, Q, X or Z, found more than once in
+	// the grid.
+	RareLetters
+)
+
+func (r Rule) String() string {
+	switch r {
+	case UnknownWord:
+		return "unknown word"
+	case ObscureWord:
+		return "obscure word"
+	case DuplicateWord:
+		return "duplicate word"
+	case SharedRoot:
+		return "shared root"
+	case TwoLetterWords:
+		return "two-letter words"
+	case Abbreviation:
+		return "abbreviation"
+	case RareLetters:
+		return "rare letters"
+	}
+	return fmt.Sprintf("Rule(%d)", int(r))
+}
+
+// Finding is a fill issue. Row and Column locate the first letter of the word
+// involved, Across telling its direction, or the square of a repeated rare
+// letter.
+type Finding struct {
+	Rule     Rule
+	Severity Severity
+	Row      int
+	Column   int
+	Across   bool
+	Word     string
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("(Row: %d, Col: %d) %s: %s: %s", f.Row+1, f.Column+1, f.Severity, f.Rule, f.Message)
+}
+
+// maxTwoLetterRatio is the highest proportion of two-letter words in a grid.
+const maxTwoLetterRatio = 0.1
+
+// rareLetters are the letters that shouldn't be found more than once in a
+// grid.
+const rareLetters = "jqxz"
+
+// suffixes are the endings removed from words to find their root, longest
+// first.
+var suffixes = []string{"ings", "ing", "ers", "er", "ed", "es", "ly", "s"}
+
+// entry is a word of the grid.
+type entry struct {
+	word   string
+	row    int
+	column int
+	across bool
+}
+
+// Lint reviews the words of a filled crossword, sorting the findings by
+// position. Unknown words are only reported if wordDict isn't nil, and
+// obscure words if it is a dictionary.TieredSource with frequencies.
+func Lint(c *crossword.Crossword, wordDict dictionary.WordSource) []Finding {
+	entries := []entry{}
+	for word := crossword.RowWord(c); word != nil; word = word.Next() {
+		entries = append(entries, entry{string(word.GetValue()), word.Row(), word.Column(), true})
+	}
+	for word := crossword.ColumnWord(c); word != nil; word = word.Next() {
+		entries = append(entries, entry{string(word.GetValue()), word.Row(), word.Column(), false})
+	}
+
+	findings := wordFindings(entries, wordDict)
+	findings = append(findings, rootFindings(entries)...)
+	findings = append(findings, twoLetterFindings(entries)...)
+	findings = append(findings, rareLetterFindings(c)...)
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if a.Row != b.Row {
+			return a.Row - b.Row
+		}
+		return a.Column - b.Column
+	})
+	return findings
+}
+
+// Penalty sums the penalty points of the findings: 1 for an info, 3 for a
+// warning and 10 for an error.
+func Penalty(findings []Finding) int {
+	penalty := 0
+	for _, finding := range findings {
+		penalty += penalties[finding.Severity]
+	}
+	return penalty
+}
+
+// Accept returns a function accepting the crosswords whose lint penalty is at
+// most maxPenalty, for crossword.CrosswordConfig.Accept.
+func Accept(wordDict dictionary.WordSource, maxPenalty int) func(c *crossword.Crossword) bool {
+	return func(c *crossword.Crossword) bool {
+		return Penalty(Lint(c, wordDict)) <= maxPenalty
+	}
+}
+
+func (e entry) direction() string {
+	if e.across {
+		return "across"
+	}
+	return "down"
+}
+
+func (e entry) finding(rule Rule, severity Severity, message string) Finding {
+	return Finding{
+		Rule:     rule,
+		Severity: severity,
+		Row:      e.row,
+		Column:   e.column,
+		Across:   e.across,
+		Word:     e.word,
+		Message:  message,
+	}
+}
+
+// wordFindings reports the unknown, obscure and abbreviated words.
+func wordFindings(entries []entry, wordDict dictionary.WordSource) []Finding {
+	tiers, ranked := wordDict.(dictionary.TieredSource)
+	ranked = ranked && tiers.Tiers() > 0
+
+	findings := []Finding{}
+	for _, e := range entries {
+		upper := strings.ToUpper(e.word)
+		switch {
+		case wordDict != nil && !wordDict.Contains(e.word):
+			findings = append(findings, e.finding(UnknownWord, Error, fmt.Sprintf("%s isn't in the dictionary", upper)))
+		case ranked:
+			if tier, ok := tiers.Tier(e.word); !ok || tier == tiers.Tiers()-1 {
+				findings = append(findings, e.finding(ObscureWord, Warning, fmt.Sprintf("%s is among the rarest words", upper)))
+			}
+		}
+		if isWord(e.word) && !strings.ContainsAny(e.word, "aeiouy") {
+			findings = append(findings, e.finding(Abbreviation, Warning, fmt.Sprintf("%s has no vowels", upper)))
+		}
+	}
+	return findings
+}
+
+// rootFindings reports the words found earlier in the grid, and the words
+// sharing their root with an earlier one.
+func rootFindings(entries []entry) []Finding {
+	findings := []Finding{}
+	roots := map[string]entry{}
+	for _, e := range entries {
+		if !isWord(e.word) {
+			continue
+		}
+		first, found := roots[root(e.word)]
+		switch {
+		case !found:
+			roots[root(e.word)] = e
+		case first.word == e.word:
+			findings = append(findings, e.finding(DuplicateWord, Error,
+				fmt.Sprintf("%s is also the %s word at (Row: %d, Col: %d)", strings.ToUpper(e.word), first.direction(), first.row+1, first.column+1)))
+		default:
+			findings = append(findings, e.finding(SharedRoot, Warning,
+				fmt.Sprintf("%s shares its root with %s", strings.ToUpper(e.word), strings.ToUpper(first.word))))
+		}
+	}
+	return findings
+}
+
+// twoLetterFindings reports the first two-letter word of a grid having too
+// many of them.
+func twoLetterFindings(entries []entry) []Finding {
+	twoLetters := []entry{}
+	for _, e := range entries {
+		if len(e.word) == 2 {
+			twoLetters = append(twoLetters, e)
+		}
+	}
+	if len(twoLetters) == 0 || float64(len(twoLetters)) <= maxTwoLetterRatio*float64(len(entries)) {
+		return []Finding{}
+	}
+	return []Finding{twoLetters[0].finding(TwoLetterWords, Warning,
+		fmt.Sprintf("%d of %d words have two letters (max %.0f%%)", len(twoLetters), len(entries), maxTwoLetterRatio*100))}
+}
+
+// rareLetterFindings reports every occurrence of a rare letter after the
+// first one.
+func rareLetterFindings(c *crossword.Crossword) []Finding {
+	findings := []Finding{}
+	seen := map[byte]int{}
+	for letter := crossword.CrosswordLetter(c); letter != nil; letter = letter.Next() {
+		value := letter.GetValue()
+		if !strings.ContainsRune(rareLetters, rune(value)) {
+			continue
+		}
+		if seen[value]++; seen[value] > 1 {
+			findings = append(findings, Finding{
+				Rule:     RareLetters,
+				Severity: Info,
+				Row:      letter.Row(),
+				Column:   letter.Column(),
+				Message:  fmt.Sprintf("%c is used %d times", value-'a'+'A', seen[value]),
+			})
+		}
+	}
+	return findings
+}
+
+// root removes the first matching suffix of a word, along with the last
+// letter of a doubled consonant, as long as 3 letters are left.
+func root(word string) string {
+	for _, suffix := range suffixes {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < 3 {
+			continue
+		}
+		if last := stem[len(stem)-1]; last == stem[len(stem)-2] && !strings.ContainsRune("aeiou", rune(last)) && len(stem) > 3 {
+			stem = stem[:len(stem)-1]
+		}
+		return stem
+	}
+	return word
+}
+
+// isWord reports whether the word is made of letters only, unlike the
+// entries of a cross-number.
+func isWord(word string) bool {
+	for i := range len(word) {
+		if word[i] < 'a' || word[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package lint_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+// positions lists the positions of the findings of every rule, written as
+// "row,column".
+func positions(findings []lint.Finding) map[lint.Rule][]string {
+	positions := map[lint.Rule][]string{}
+	for _, finding := range findings {
+		positions[finding.Rule] = append(positions[finding.Rule], fmt.Sprintf("%d,%d", finding.Row, finding.Column))
+	}
+	return positions
+}
+
+func TestLint(t *testing.T) {
+	grid, err := crossword.ParseCrossword(`
+		runs.run
+		........
+		tv.jazz.
+		........
+		jinx.zzz
+	`)
+	assert.NoError(t, err)
+	wordDict := dictionary.NewWordDictionaryFromWords([]string{"runs", "run", "tv", "jazz", "jinx"})
+
+	findings := lint.Lint(grid, wordDict)
+	assert.Equal(t, map[lint.Rule][]string{
+		lint.UnknownWord:    {"4,5"},
+		lint.SharedRoot:     {"0,5"},
+		lint.TwoLetterWords: {"2,0"},
+		lint.Abbreviation:   {"2,0", "4,5"},
+		lint.RareLetters:    {"2,6", "4,0", "4,5", "4,6", "4,7"},
+	}, positions(findings))
+	assert.Equal(t, "(Row: 1, Col: 6) warning: shared root: RUN shares its root with RUNS", findings[0].String())
+	assert.Equal(t, 10+4*3+5*1, lint.Penalty(findings))
+
+	assert.True(t, lint.Accept(wordDict, 27)(grid))
+	assert.False(t, lint.Accept(wordDict, 26)(grid))
+}
+
+func TestLintDuplicatesAndObscureWords(t *testing.T) {
+	grid, err := crossword.ParseCrossword(`
+		cat.
+		a..c
+		tent
+	`)
+	assert.NoError(t, err)
+	wordDict, err := dictionary.NewWordDictionaryFromWords([]string{"cat", "tent", "ct"}).
+		WithFrequencies(strings.NewReader("cat\ntent\n"))
+	assert.NoError(t, err)
+
+	findings := lint.Lint(grid, wordDict)
+	assert.Equal(t, map[lint.Rule][]string{
+		lint.DuplicateWord:  {"0,0"},
+		lint.ObscureWord:    {"1,3"},
+		lint.TwoLetterWords: {"1,3"},
+		lint.Abbreviation:   {"1,3"},
+	}, positions(findings))
+	for _, finding := range findings {
+		if finding.Rule == lint.DuplicateWord {
+			assert.False(t, finding.Across)
+			assert.Equal(t, "CAT is also the across word at (Row: 1, Col: 1)", finding.Message)
+		}
+	}
+
+	// without a dictionary, words aren't checked against it
+	assert.Equal(t, map[lint.Rule][]string{
+		lint.DuplicateWord:  {"0,0"},
+		lint.TwoLetterWords: {"1,3"},
+		lint.Abbreviation:   {"1,3"},
+	}, positions(lint.Lint(grid, nil)))
+}
+
+func TestLintGeneratedCrossword(t *testing.T) {
+	wordDict := dictionary.NewWordDictionary()
+	result, err := crossword.NewCrossword(crossword.CrosswordConfig{
+		Rows:     7,
+		Cols:     7,
+		Threads:  4,
+		WordDict: wordDict,
+		Accept:   lint.Accept(wordDict, 15),
+	})
+
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, lint.Penalty(lint.Lint(result.Crossword, wordDict)), 15)
+}
@@ -0,0 +1,47 @@
+package wordsearch
+
+import "fmt"
+
+// Direction is one of the 8 directions a word can be read in a word search.
+type Direction int
+
+const (
+	East Direction = iota
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+	North
+	NorthEast
+)
+
+var directionNames = [...]string{"e", "se", "s", "sw", "w", "nw", "n", "ne"}
+
+var directionSteps = [...][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+// AllDirections returns the 8 directions, starting with East and going
+// clockwise.
+func AllDirections() []Direction {
+	return []Direction{East, SouthEast, South, SouthWest, West, NorthWest, North, NorthEast}
+}
+
+// ParseDirection returns the direction with the given compass name (e.g. "se").
+func ParseDirection(name string) (Direction, error) {
+	for i, directionName := range directionNames {
+		if name == directionName {
+			return Direction(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown direction %q (available: %v)", name, directionNames)
+}
+
+// Step returns the row and column offsets between two consecutive letters of a
+// word read in the direction.
+func (d Direction) Step() (int, int) {
+	return directionSteps[d][0], directionSteps[d][1]
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
@@ -0,0 +1,348 @@
+package wordsearch
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+)
+
+// maxAttempts is the number of times the words are laid out from scratch
+// before giving up.
+const maxAttempts = 100
+
+// maxRefills is the number of random letters redrawn to remove accidental
+// occurrences of the words before a layout is abandoned.
+const maxRefills = 1000
+
+// ErrNoPlacement is returned by NewWordSearch when the words can't all be
+// placed in the grid with every word appearing exactly once.
+var ErrNoPlacement = errors.New("the words can't be placed in the word search")
+
+// ErrTooFewWords is returned by NewWordSearch when the dictionary has fewer
+// than Count words of the configured lengths that can't be read inside one
+// another.
+var ErrTooFewWords = errors.New("the dictionary has too few words to sample")
+
+type WordSearchConfig struct {
+	Rows int
+	Cols int
+	// Words are the words to hide in the grid. When empty, Count words of
+	// MinLength to MaxLength letters are sampled at random from WordDict
+	// instead. Sampling by theme isn't supported.
+	Words     []string
+	WordDict  dictionary.WordSource
+	Count     int
+	MinLength int
+	MaxLength int
+	// Directions restricts the directions the words are written in. All 8
+	// directions are used when nil.
+	Directions []Direction
+	// Overlaps allows words to share squares holding the same letter.
+	Overlaps bool
+	Seed     int64
+}
+
+// PlacedWord is a word hidden in a word search, starting at Row and Column and
+// read in Direction.
+type PlacedWord struct {
+	Word      string
+	Row       int
+	Column    int
+	Direction Direction
+}
+
+// squares returns the positions of the letters of the word in a grid with the
+// given number of columns.
+func (w PlacedWord) squares(columns int) []int {
+	rowStep, columnStep := w.Direction.Step()
+	squares := make([]int, len(w.Word))
+	for i := range squares {
+		squares[i] = (w.Row+i*rowStep)*columns + w.Column + i*columnStep
+	}
+	return squares
+}
+
+type WordSearch struct {
+	grid  *crossword.Crossword
+	words []PlacedWord
+}
+
+type WordSearchResult struct {
+	WordSearch *WordSearch
+	Seed       int64
+}
+
+// Grid returns the letters of the word search.
+func (ws *WordSearch) Grid() *crossword.Crossword {
+	return ws.grid
+}
+
+// Words returns the hidden words, in the order they were placed.
+func (ws *WordSearch) Words() []PlacedWord {
+	return ws.words
+}
+
+// IsAnswer reports whether the square belongs to a hidden word.
+func (ws *WordSearch) IsAnswer(row, column int) bool {
+	for _, word := range ws.words {
+		if slices.Contains(word.squares(ws.grid.Columns()), row*ws.grid.Columns()+column) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWordSearch hides words in a grid of random letters, each word appearing
+// exactly once in the grid. The returned seed reproduces the word search.
+func NewWordSearch(config WordSearchConfig) (WordSearchResult, error) {
+	if config.Rows < 1 || config.Cols < 1 {
+		return WordSearchResult{}, fmt.Errorf("invalid dimensions %dx%d", config.Rows, config.Cols)
+	}
+	words, err := normalizeWords(config.Words)
+	if err != nil {
+		return WordSearchResult{}, err
+	}
+	if len(words) == 0 && config.Count < 1 {
+		return WordSearchResult{}, fmt.Errorf("no words to place")
+	}
+	directions := config.Directions
+	if len(directions) == 0 {
+		directions = AllDirections()
+	}
+
+	seed := config.Seed
+	for seed == 0 {
+		seed = rand.Int63()
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	for range maxAttempts {
+		attemptWords := words
+		if len(attemptWords) == 0 {
+			if attemptWords, err = sampleWords(config, random); err != nil {
+				return WordSearchResult{}, err
+			}
+		}
+		if ws := newWordSearch(config.Rows, config.Cols, attemptWords, directions, config.Overlaps, random); ws != nil {
+			return WordSearchResult{WordSearch: ws, Seed: seed}, nil
+		}
+	}
+	return WordSearchResult{}, ErrNoPlacement
+}
+
+// normalizeWords lowercases the given words and checks that they are made of
+// letters and that none of them can be read inside another one, which would
+// make it appear twice.
+func normalizeWords(words []string) ([]string, error) {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len(word) < 2 {
+			return nil, fmt.Errorf("word %q is shorter than 2 letters", word)
+		}
+		for i := range len(word) {
+			if word[i] < 'a' || word[i] > 'z' {
+				return nil, fmt.Errorf("word %q contains %q, only letters are allowed", word, word[i])
+			}
+		}
+		for _, other := range normalized {
+			if contains(word, other) || contains(other, word) {
+				return nil, fmt.Errorf("word %q can be read inside %q", other, word)
+			}
+		}
+		normalized = append(normalized, word)
+	}
+	return normalized, nil
+}
+
+// contains reports whether the word can be read inside the other word, either
+// forwards or backwards.
+func contains(other, word string) bool {
+	return strings.Contains(other, word) || strings.Contains(other, reverse(word))
+}
+
+func reverse(word string) string {
+	reversed := []byte(word)
+	slices.Reverse(reversed)
+	return string(reversed)
+}
+
+// sampleWords picks config.Count words from the dictionary that fit the grid
+// and can't be read inside one another, or returns ErrTooFewWords.
+func sampleWords(config WordSearchConfig, random *rand.Rand) ([]string, error) {
+	minLength := max(config.MinLength, 2)
+	maxLength := max(config.Rows, config.Cols)
+	if config.MaxLength > 0 {
+		maxLength = min(maxLength, config.MaxLength)
+	}
+
+	candidates := []string{}
+	for length := minLength; length <= maxLength; length++ {
+		for _, index := range config.WordDict.Candidates(make([]byte, length)) {
+			candidates = append(candidates, config.WordDict.Word(index))
+		}
+	}
+
+	words := []string{}
+	for _, i := range random.Perm(len(candidates)) {
+		word := candidates[i]
+		if slices.ContainsFunc(words, func(other string) bool {
+			return contains(word, other) || contains(other, word)
+		}) {
+			continue
+		}
+		words = append(words, word)
+		if len(words) == config.Count {
+			return words, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d of %d words", ErrTooFewWords, len(words), config.Count)
+}
+
+// newWordSearch places the words, longest first, at random positions and
+// fills the remaining squares with random letters. It returns nil if a word
+// doesn't fit or if accidental occurrences of the words can't be removed.
+func newWordSearch(rows, columns int, words []string, directions []Direction, overlaps bool, random *rand.Rand) *WordSearch {
+	ws := &WordSearch{grid: crossword.NewGrid(rows, columns)}
+
+	words = slices.Clone(words)
+	slices.SortStableFunc(words, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	for _, word := range words {
+		placement, ok := ws.place(word, directions, overlaps, random)
+		if !ok {
+			return nil
+		}
+		ws.words = append(ws.words, placement)
+	}
+
+	hidden := make([]bool, rows*columns)
+	for _, word := range ws.words {
+		for _, square := range word.squares(columns) {
+			hidden[square] = true
+		}
+	}
+	for letter := crossword.CrosswordLetter(ws.grid); letter != nil; letter = letter.Next() {
+		if letter.IsEmpty() {
+			letter.SetValue(randomLetter(random))
+		}
+	}
+
+	// redraw a random letter of every accidental occurrence until each word
+	// appears only where it was placed
+	for range maxRefills {
+		extra := ws.extraOccurrences()
+		if len(extra) == 0 {
+			return ws
+		}
+		free := slices.DeleteFunc(extra[0].squares(columns), func(square int) bool {
+			return hidden[square]
+		})
+		if len(free) == 0 {
+			return nil
+		}
+		square := free[random.Intn(len(free))]
+		crossword.CrosswordLetterAt(ws.grid, square/columns, square%columns).SetValue(randomLetter(random))
+	}
+	return nil
+}
+
+// place picks a random position and direction where the word fits, only
+// crossing other words at matching letters when overlaps are allowed.
+func (ws *WordSearch) place(word string, directions []Direction, overlaps bool, random *rand.Rand) (PlacedWord, bool) {
+	rows, columns := ws.grid.Rows(), ws.grid.Columns()
+	placements := []PlacedWord{}
+	for row := range rows {
+		for column := range columns {
+			for _, direction := range directions {
+				rowStep, columnStep := direction.Step()
+				endRow, endColumn := row+(len(word)-1)*rowStep, column+(len(word)-1)*columnStep
+				if endRow < 0 || endRow >= rows || endColumn < 0 || endColumn >= columns {
+					continue
+				}
+				placements = append(placements, PlacedWord{Word: word, Row: row, Column: column, Direction: direction})
+			}
+		}
+	}
+	random.Shuffle(len(placements), func(i, j int) {
+		placements[i], placements[j] = placements[j], placements[i]
+	})
+
+	for _, placement := range placements {
+		if !ws.fits(placement, overlaps) {
+			continue
+		}
+		for i, square := range placement.squares(columns) {
+			crossword.CrosswordLetterAt(ws.grid, square/columns, square%columns).SetValue(word[i])
+		}
+		return placement, true
+	}
+	return PlacedWord{}, false
+}
+
+// fits reports whether the squares of the placement are empty, or hold the
+// same letters when overlaps are allowed.
+func (ws *WordSearch) fits(placement PlacedWord, overlaps bool) bool {
+	columns := ws.grid.Columns()
+	for i, square := range placement.squares(columns) {
+		letter := crossword.CrosswordLetterAt(ws.grid, square/columns, square%columns)
+		if !letter.IsEmpty() && (!overlaps || letter.GetValue() != placement.Word[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// extraOccurrences returns the occurrences of the hidden words in the grid
+// other than the placed ones.
+func (ws *WordSearch) extraOccurrences() []PlacedWord {
+	rows, columns := ws.grid.Rows(), ws.grid.Columns()
+	extra := []PlacedWord{}
+	for _, word := range ws.words {
+		placed := word.squares(columns)
+		for row := range rows {
+			for column := range columns {
+				for _, direction := range AllDirections() {
+					occurrence := PlacedWord{Word: word.Word, Row: row, Column: column, Direction: direction}
+					if !ws.reads(occurrence) {
+						continue
+					}
+					// a palindrome can also be read backwards where it was placed
+					squares := occurrence.squares(columns)
+					slices.Sort(squares)
+					if slices.Equal(squares, slices.Sorted(slices.Values(placed))) {
+						continue
+					}
+					extra = append(extra, occurrence)
+				}
+			}
+		}
+	}
+	return extra
+}
+
+// reads reports whether the word can be read at the given position.
+func (ws *WordSearch) reads(word PlacedWord) bool {
+	rows, columns := ws.grid.Rows(), ws.grid.Columns()
+	rowStep, columnStep := word.Direction.Step()
+	for i := range len(word.Word) {
+		row, column := word.Row+i*rowStep, word.Column+i*columnStep
+		if row < 0 || row >= rows || column < 0 || column >= columns {
+			return false
+		}
+		if crossword.CrosswordLetterAt(ws.grid, row, column).GetValue() != word.Word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func randomLetter(random *rand.Rand) byte {
+	return byte('a' + random.Intn(26))
+}
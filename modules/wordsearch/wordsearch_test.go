@@ -0,0 +1,110 @@
+package wordsearch_test
+
+import (
+	"testing"
+
+	"github.com/ahboujelben/go-crossword/modules/crossword"
+	"github.com/ahboujelben/go-crossword/modules/dictionary"
+	"github.com/ahboujelben/go-crossword/modules/wordsearch"
+	"github.com/stretchr/testify/assert"
+)
+
+// occurrences counts the positions and directions the word can be read at.
+func occurrences(c *crossword.Crossword, word string) int {
+	count := 0
+	for row := range c.Rows() {
+		for column := range c.Columns() {
+			for _, direction := range wordsearch.AllDirections() {
+				rowStep, columnStep := direction.Step()
+				i := 0
+				for ; i < len(word); i++ {
+					r, col := row+i*rowStep, column+i*columnStep
+					if r < 0 || r >= c.Rows() || col < 0 || col >= c.Columns() ||
+						crossword.CrosswordLetterAt(c, r, col).GetValue() != word[i] {
+						break
+					}
+				}
+				if i == len(word) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+func TestNewWordSearch(t *testing.T) {
+	words := []string{"Apple", "banana", "cherry", "grape", "melon", "lemon", "peach", "plum", "level"}
+	for _, overlaps := range []bool{false, true} {
+		result, err := wordsearch.NewWordSearch(wordsearch.WordSearchConfig{
+			Rows:     10,
+			Cols:     10,
+			Words:    words,
+			Overlaps: overlaps,
+		})
+		assert.NoError(t, err)
+
+		ws := result.WordSearch
+		assert.Len(t, ws.Words(), len(words))
+		for _, word := range ws.Words() {
+			expected := 1
+			if word.Word == "level" {
+				// a palindrome is read in both directions
+				expected = 2
+			}
+			assert.Equal(t, expected, occurrences(ws.Grid(), word.Word), word.Word)
+			assert.True(t, ws.IsAnswer(word.Row, word.Column))
+		}
+		for letter := crossword.CrosswordLetter(ws.Grid()); letter != nil; letter = letter.Next() {
+			assert.False(t, letter.IsEmpty())
+		}
+
+		replayed, err := wordsearch.NewWordSearch(wordsearch.WordSearchConfig{
+			Rows:     10,
+			Cols:     10,
+			Words:    words,
+			Overlaps: overlaps,
+			Seed:     result.Seed,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, ws.Grid().String(), replayed.WordSearch.Grid().String())
+	}
+}
+
+func TestNewWordSearchFromDictionary(t *testing.T) {
+	result, err := wordsearch.NewWordSearch(wordsearch.WordSearchConfig{
+		Rows:       12,
+		Cols:       12,
+		WordDict:   dictionary.NewWordDictionary(),
+		Count:      10,
+		MinLength:  5,
+		MaxLength:  8,
+		Directions: []wordsearch.Direction{wordsearch.East, wordsearch.South},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.WordSearch.Words(), 10)
+	for _, word := range result.WordSearch.Words() {
+		assert.GreaterOrEqual(t, len(word.Word), 5)
+		assert.LessOrEqual(t, len(word.Word), 8)
+		assert.Contains(t, []wordsearch.Direction{wordsearch.East, wordsearch.South}, word.Direction)
+	}
+}
+
+func TestNewWordSearchErrors(t *testing.T) {
+	_, err := wordsearch.NewWordSearch(wordsearch.WordSearchConfig{Rows: 5, Cols: 5, Words: []string{"party", "art"}})
+	assert.Error(t, err)
+
+	_, err = wordsearch.NewWordSearch(wordsearch.WordSearchConfig{Rows: 5, Cols: 5, Words: []string{"elephant"}})
+	assert.ErrorIs(t, err, wordsearch.ErrNoPlacement)
+
+	_, err = wordsearch.NewWordSearch(wordsearch.WordSearchConfig{
+		Rows:     5,
+		Cols:     5,
+		WordDict: dictionary.NewWordDictionaryFromWords([]string{"cat", "dog", "category"}),
+		Count:    3,
+	})
+	assert.ErrorIs(t, err, wordsearch.ErrTooFewWords)
+
+	_, err = wordsearch.ParseDirection("up")
+	assert.Error(t, err)
+}